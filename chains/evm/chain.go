@@ -8,7 +8,6 @@ import (
 	"github.com/ClipFinance/relay-lib/common/types"
 	"github.com/ClipFinance/relay-lib/connectionmonitor"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -33,12 +32,18 @@ type evm struct {
 	solverAddressMutex sync.RWMutex       // Mutex for solver address.
 
 	// Protected fields with their own mutexes.
-	clientMutex sync.RWMutex      // Mutex for client.
+	clientMutex sync.RWMutex      // Mutex for client and clientURL.
 	client      *ethclient.Client // Ethereum client.
+	clientURL   string            // URL of the RPC endpoint the client is connected to.
+
+	rpcPool     *connectionmonitor.RPCPool // Health ranked RPC endpoints of the chain.
+	poolClients *endpointClients           // Clients of the pool endpoints other than the current one.
 
 	signerMutex sync.RWMutex  // Mutex for signer.
 	signer      signer.Signer // Signer for signing transactions.
 
+	wallets []*wallet // Solver wallets transactions are sent from, the wallet of the signer first.
+
 	eventHandlerMutex sync.RWMutex          // Mutex for event handler.
 	eventHandler      *handler.EventHandler // Event handler for handling chain events.
 
@@ -58,15 +63,36 @@ type evm struct {
 func NewEvmChain(config *types.ChainConfig, logger *logrus.Logger) (types.Chain, error) {
 	ctx := context.Background()
 
-	client, err := ethclient.Dial(config.RpcUrl)
+	chain := &evm{
+		config:      config,
+		logger:      logger,
+		poolClients: newEndpointClients(),
+	}
+
+	rpcPool, err := connectionmonitor.NewRPCPool(
+		append([]string{config.RpcUrl}, config.RpcUrls...),
+		&evmConnectionManager{chain: chain},
+		logger,
+		config.Name,
+	)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create client")
+		return nil, errors.Wrap(err, "failed to create rpc pool")
 	}
+	chain.rpcPool = rpcPool
 
-	chain := &evm{
-		config: config,
-		logger: logger,
-		client: client,
+	connectCtx, cancel := context.WithTimeout(ctx, waitTimeout)
+	err = chain.connectEndpoint(connectCtx, chain.failoverCandidates())
+	cancel()
+	if err != nil {
+		// Keep the previous behaviour of starting without a reachable node, the monitor reconnects later.
+		logger.WithField("chain", config.Name).WithError(err).Warn("No RPC endpoint answered, using the configured RPC URL")
+
+		client, err := ethclient.Dial(config.RpcUrl)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create client")
+		}
+		chain.client = client
+		chain.clientURL = config.RpcUrl
 	}
 
 	if config.SolverAddress != "" {
@@ -80,22 +106,18 @@ func NewEvmChain(config *types.ChainConfig, logger *logrus.Logger) (types.Chain,
 	builder := chainmanager.NewChainBuilder(config)
 	builder.WithGasEstimator(chain)
 
-	if config.PrivateKey != "" {
-		privKey, err := crypto.HexToECDSA(config.PrivateKey)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to parse private key")
-		}
-
-		signer, err := signer.NewSigner(privKey)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to create signer")
-		}
+	wallets, err := newChainWallets(config)
+	if err != nil {
+		return nil, err
+	}
 
+	if len(wallets) > 0 {
 		chain.signerMutex.Lock()
-		chain.signer = signer
+		chain.signer = wallets[0].signer
 		chain.signerMutex.Unlock()
 
-		chain.solverAddress = signer.Address()
+		chain.wallets = wallets
+		chain.solverAddress = wallets[0].signer.Address()
 		builder.WithTransactionSender(chain)
 	}
 
@@ -107,7 +129,7 @@ func NewEvmChain(config *types.ChainConfig, logger *logrus.Logger) (types.Chain,
 }
 
 // Close should be called when the chain is no longer needed.
-// It stops the connection monitor, closes the client, and stops the event handler.
+// It stops the connection monitor, closes the clients, and stops the event handler.
 func (e *evm) Close() {
 	e.monitorMutex.Lock()
 	if e.monitor != nil {
@@ -122,6 +144,8 @@ func (e *evm) Close() {
 	}
 	e.clientMutex.Unlock()
 
+	e.poolClients.closeAll()
+
 	e.eventHandlerMutex.Lock()
 	if e.eventHandler != nil {
 		e.eventHandler.Stop()
@@ -3,7 +3,6 @@ package evm
 import (
 	"context"
 	"github.com/ClipFinance/relay-lib/connectionmonitor"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/pkg/errors"
 )
 
@@ -28,53 +27,43 @@ func (e *evm) initMonitor(ctx context.Context) error {
 	return e.monitor.Start(ctx)
 }
 
-// CheckConnection checks the connection to the Ethereum client by retrieving the current block number.
+// CheckConnection probes the RPC endpoints of the pool and checks the endpoint of the current client.
 //
 // Parameters:
 // - ctx: the context for managing the connection check.
 //
 // Returns:
-// - error: an error if the client is not initialized or if there is an issue retrieving the block number.
+// - error: an error if the client is not initialized, if the current endpoint is unhealthy,
+// or if a healthy endpoint scores better by more than the failover margin.
 func (w *evmConnectionManager) CheckConnection(ctx context.Context) error {
 	w.chain.clientMutex.RLock()
-	client := w.chain.client
+	client, clientURL := w.chain.client, w.chain.clientURL
 	w.chain.clientMutex.RUnlock()
 
 	if client == nil {
 		return errors.New("client not initialized")
 	}
 
-	_, err := client.BlockNumber(ctx)
-	return err
+	w.chain.rpcPool.CheckHealth(ctx)
+
+	if !w.chain.rpcPool.ShouldFailover(clientURL, w.chain.failoverCandidates()) {
+		return nil
+	}
+
+	if health, ok := w.chain.rpcPool.Health(clientURL); ok && health.Healthy {
+		return errors.Errorf("rpc endpoint %s is degraded, a better endpoint is available", clientURL)
+	}
+
+	return errors.Errorf("rpc endpoint %s is unhealthy", clientURL)
 }
 
-// Reconnect re-establishes the connection to the Ethereum client and updates the event handler with the new client.
+// Reconnect switches the client to the best answering RPC endpoint and updates the event handler with the new client.
 //
 // Parameters:
 // - ctx: the context for managing the reconnection process.
 //
 // Returns:
-// - error: an error if there is an issue dialing the new client or updating the event handler.
+// - error: an error if none of the endpoints answers.
 func (w *evmConnectionManager) Reconnect(ctx context.Context) error {
-	w.chain.clientMutex.Lock()
-	defer w.chain.clientMutex.Unlock()
-
-	if w.chain.client != nil {
-		w.chain.client.Close()
-	}
-
-	client, err := ethclient.Dial(w.chain.config.RpcUrl)
-	if err != nil {
-		return err
-	}
-
-	w.chain.client = client
-
-	w.chain.eventHandlerMutex.Lock()
-	if w.chain.eventHandler != nil {
-		w.chain.eventHandler.UpdateClient(client)
-	}
-	w.chain.eventHandlerMutex.Unlock()
-
-	return nil
+	return w.chain.connectEndpoint(ctx, w.chain.failoverCandidates())
 }
@@ -29,21 +29,41 @@ type GasPriceData struct {
 // - uint64: the estimated gas required for the transaction.
 // - error: an error if the client or signer is not initialized or if the gas estimation fails.
 func (e *evm) EstimateGas(ctx context.Context, toAddress string, value *big.Int, data []byte) (uint64, error) {
-	e.clientMutex.RLock()
-	client := e.client
-	e.clientMutex.RUnlock()
-
 	e.signerMutex.RLock()
 	signer := e.signer
 	e.signerMutex.RUnlock()
 
-	if client == nil || signer == nil {
-		return 0, errors.New("client or signer not initialized")
+	if signer == nil {
+		return 0, errors.New("signer not initialized")
+	}
+
+	return e.estimateGas(ctx, signer.Address(), toAddress, value, data)
+}
+
+// estimateGas estimates the gas required for a transaction sent from the given account.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - from: the account sending the transaction.
+// - toAddress: the recipient address of the transaction.
+// - value: the amount of Ether to send with the transaction.
+// - data: the input data for the transaction.
+//
+// Returns:
+// - uint64: the estimated gas required for the transaction.
+// - error: an error if the client is not initialized or if the gas estimation fails.
+func (e *evm) estimateGas(ctx context.Context, from common.Address, toAddress string, value *big.Int, data []byte) (uint64, error) {
+	e.clientMutex.RLock()
+	client := e.client
+	e.clientMutex.RUnlock()
+
+	if client == nil {
+		return 0, errors.New("client not initialized")
 	}
 
 	to := common.HexToAddress(toAddress)
 	msg := ethereum.CallMsg{
-		From:     signer.Address(),
+		From:     from,
 		To:       &to,
 		Value:    value,
 		GasPrice: nil,
@@ -107,14 +127,14 @@ func (e *evm) getEIP1559GasPrice(ctx context.Context) (*GasPriceData, error) {
 	}, nil
 }
 
-func (e *evm) estimateLegacyGasPrice(ctx context.Context, toAddress string, value *big.Int, data []byte) (*big.Int, error) {
+func (e *evm) estimateLegacyGasPrice(ctx context.Context, from common.Address, toAddress string, value *big.Int, data []byte) (*big.Int, error) {
 	to := common.HexToAddress(toAddress)
 
 	// TODO: refactor this using lineal_estimateGas to avoid if-else condition.
 	if e.config.ChainID == 59144 {
 		var gasEstimate map[string]string
 		err := e.client.Client().CallContext(ctx, &gasEstimate, "linea_estimateGas", map[string]interface{}{
-			"from":  e.signer.Address(),
+			"from":  from,
 			"to":    to.Hex(),
 			"value": value,
 			"data":  data,
@@ -221,6 +221,67 @@ const ERC20ABI = `
 ],
 "name": "Transfer",
 "type": "event"
+},
+{
+"inputs": [
+{
+"name": "sender",
+"type": "address"
+},
+{
+"name": "balance",
+"type": "uint256"
+},
+{
+"name": "needed",
+"type": "uint256"
+}
+],
+"name": "ERC20InsufficientBalance",
+"type": "error"
+},
+{
+"inputs": [
+{
+"name": "spender",
+"type": "address"
+},
+{
+"name": "allowance",
+"type": "uint256"
+},
+{
+"name": "needed",
+"type": "uint256"
+}
+],
+"name": "ERC20InsufficientAllowance",
+"type": "error"
+},
+{
+"inputs": [
+{
+"name": "sender",
+"type": "address"
+}
+],
+"name": "ERC20InvalidSender",
+"type": "error"
+},
+{
+"inputs": [
+{
+"name": "receiver",
+"type": "address"
+}
+],
+"name": "ERC20InvalidReceiver",
+"type": "error"
+},
+{
+"inputs": [],
+"name": "EnforcedPause",
+"type": "error"
 }
 ]
 `
@@ -0,0 +1,24 @@
+package generated
+
+const RelayReceiverABI = `
+[
+{
+"anonymous": false,
+"inputs": [],
+"name": "FundsForwarded",
+"type": "event"
+},
+{
+"anonymous": false,
+"inputs": [
+{
+"indexed": false,
+"name": "data",
+"type": "bytes"
+}
+],
+"name": "FundsForwardedWithData",
+"type": "event"
+}
+]
+`
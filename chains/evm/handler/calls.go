@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"github.com/ClipFinance/relay-lib/chains/evm/utils"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"math/big"
+)
+
+// tracerTransaction is the Geth method tracing a single transaction.
+const tracerTransaction = "debug_traceTransaction"
+
+// emittingCall returns the call that emitted a log, found in the call tracer trace of its transaction.
+// Without debug_traceTransaction, the log is attributed to the transaction sender and value, which is exact
+// only for logs of the contract called by the transaction itself.
+//
+// Parameters:
+// - log: the event log.
+// - tx: the transaction that emitted the log.
+// - receipt: the receipt of the transaction.
+//
+// Returns:
+// - *utils.Call: the call that emitted the log.
+// - error: an error if the transaction cannot be traced or the log is not found in the trace.
+func (h *EventHandler) emittingCall(log ethtypes.Log, tx *ethtypes.Transaction, receipt *ethtypes.Receipt) (*utils.Call, error) {
+	ctx, cancel := context.WithTimeout(h.getContext(), contextTimeout)
+	defer cancel()
+
+	var trace callFrame
+	err := h.getClient().Client().CallContext(ctx, &trace, tracerTransaction, log.TxHash, map[string]interface{}{
+		"tracer":       "callTracer",
+		"tracerConfig": map[string]bool{"withLog": true},
+	})
+	if err != nil {
+		if !isMethodUnsupported(err) {
+			return nil, errors.Wrap(err, "failed to trace transaction")
+		}
+
+		sender, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(tx.ChainId()), tx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get transaction sender")
+		}
+		if tx.To() == nil || *tx.To() != log.Address {
+			h.logger.WithFields(logrus.Fields{
+				"chain":  h.chainConfig.Name,
+				"txHash": log.TxHash.Hex(),
+			}).Warn("Node does not support transaction traces, attributing the event of an inner call to the transaction")
+		}
+		return &utils.Call{From: sender, Value: tx.Value()}, nil
+	}
+
+	// Identical logs of a transaction are told apart by their order.
+	occurrence := 0
+	for _, other := range receipt.Logs {
+		if other.Index < log.Index && isSameLog(other.Address, other.Topics, other.Data, log) {
+			occurrence++
+		}
+	}
+
+	call := findEmittingCall(&trace, &trace, log, &occurrence)
+	if call == nil {
+		return nil, errors.New("log not found in transaction trace")
+	}
+
+	return call, nil
+}
+
+// findEmittingCall walks a call frame in execution order and returns the call that emitted the log.
+// A delegate call runs in the context of its caller, so its logs are attributed to the calling frame.
+//
+// Parameters:
+// - frame: the call frame to walk.
+// - valueFrame: the frame whose sender and value apply to the logs of the frame.
+// - log: the event log.
+// - skip: the number of identical logs emitted before the log, decremented as they are passed.
+//
+// Returns:
+// - *utils.Call: the call that emitted the log, nil if it is not emitted within the frame.
+func findEmittingCall(frame, valueFrame *callFrame, log ethtypes.Log, skip *int) *utils.Call {
+	// Logs of a failed call are reverted with it.
+	if frame.Error != "" {
+		return nil
+	}
+	if frame.Type != "DELEGATECALL" {
+		valueFrame = frame
+	}
+
+	for position := 0; position <= len(frame.Calls); position++ {
+		for _, frameLog := range frame.Logs {
+			if int(frameLog.Position) != position || !isSameLog(frameLog.Address, frameLog.Topics, frameLog.Data, log) {
+				continue
+			}
+			if *skip > 0 {
+				*skip--
+				continue
+			}
+
+			value := new(big.Int)
+			if valueFrame.Value != nil {
+				value = valueFrame.Value.ToInt()
+			}
+			return &utils.Call{From: valueFrame.From, Value: value}
+		}
+
+		if position < len(frame.Calls) {
+			if call := findEmittingCall(&frame.Calls[position], valueFrame, log, skip); call != nil {
+				return call
+			}
+		}
+	}
+
+	return nil
+}
+
+// isSameLog reports whether a log has the address, topics and data of another log.
+func isSameLog(address common.Address, topics []common.Hash, data []byte, log ethtypes.Log) bool {
+	if address != log.Address || len(topics) != len(log.Topics) || !bytes.Equal(data, log.Data) {
+		return false
+	}
+	for i, topic := range topics {
+		if topic != log.Topics[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"testing"
+)
+
+func TestFindEmittingCall(t *testing.T) {
+	var (
+		bundler    = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		entryPoint = common.HexToAddress("0x2000000000000000000000000000000000000002")
+		walletA    = common.HexToAddress("0x3000000000000000000000000000000000000003")
+		walletB    = common.HexToAddress("0x4000000000000000000000000000000000000004")
+		receiver   = common.HexToAddress("0x5000000000000000000000000000000000000005")
+		topic      = common.HexToHash("0xaa")
+	)
+
+	forwardLog := func(data string) callLog {
+		return callLog{Address: receiver, Topics: []common.Hash{topic}, Data: common.FromHex(data)}
+	}
+	receiverCall := func(from common.Address, value int64, data string) callFrame {
+		return callFrame{
+			Type:  "CALL",
+			From:  from,
+			To:    &receiver,
+			Value: (*hexutil.Big)(big.NewInt(value)),
+			Logs:  []callLog{forwardLog(data)},
+		}
+	}
+	walletCall := func(wallet common.Address, calls ...callFrame) callFrame {
+		return callFrame{Type: "CALL", From: entryPoint, To: &wallet, Value: new(hexutil.Big), Calls: calls}
+	}
+
+	// A bundle of two smart wallet deposits, the second wallet depositing twice with the same data.
+	bundle := callFrame{
+		Type:  "CALL",
+		From:  bundler,
+		To:    &entryPoint,
+		Value: new(hexutil.Big),
+		Calls: []callFrame{
+			walletCall(walletA, receiverCall(walletA, 100, "0x01")),
+			walletCall(walletB, receiverCall(walletB, 200, "0x02"), receiverCall(walletB, 300, "0x02")),
+		},
+	}
+
+	// A deposit through a receiver proxy, its logs are emitted by the delegate call.
+	proxied := callFrame{
+		Type:  "CALL",
+		From:  walletA,
+		To:    &receiver,
+		Value: (*hexutil.Big)(big.NewInt(400)),
+		Calls: []callFrame{{
+			Type: "DELEGATECALL",
+			From: receiver,
+			To:   &entryPoint,
+			Logs: []callLog{forwardLog("0x04")},
+		}},
+	}
+
+	// A reverted deposit followed by a successful one with the same data.
+	reverted := callFrame{
+		Type:  "CALL",
+		From:  bundler,
+		To:    &walletA,
+		Value: new(hexutil.Big),
+		Calls: []callFrame{
+			func() callFrame {
+				call := receiverCall(walletA, 500, "0x05")
+				call.Error = "execution reverted"
+				return call
+			}(),
+			receiverCall(walletA, 600, "0x05"),
+		},
+	}
+
+	tests := []struct {
+		name      string
+		trace     callFrame
+		data      string
+		skip      int
+		wantFrom  common.Address
+		wantValue int64
+		wantNil   bool
+	}{
+		{name: "first wallet of bundle", trace: bundle, data: "0x01", wantFrom: walletA, wantValue: 100},
+		{name: "second wallet of bundle", trace: bundle, data: "0x02", wantFrom: walletB, wantValue: 200},
+		{name: "identical log of second wallet", trace: bundle, data: "0x02", skip: 1, wantFrom: walletB, wantValue: 300},
+		{name: "unknown log", trace: bundle, data: "0x03", wantNil: true},
+		{name: "delegate call", trace: proxied, data: "0x04", wantFrom: walletA, wantValue: 400},
+		{name: "reverted call", trace: reverted, data: "0x05", wantFrom: walletA, wantValue: 600},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := ethtypes.Log{Address: receiver, Topics: []common.Hash{topic}, Data: common.FromHex(tt.data)}
+			skip := tt.skip
+
+			call := findEmittingCall(&tt.trace, &tt.trace, log, &skip)
+			if tt.wantNil {
+				if call != nil {
+					t.Fatalf("findEmittingCall() = %+v, want nil", call)
+				}
+				return
+			}
+			if call == nil {
+				t.Fatal("findEmittingCall() = nil")
+			}
+			if call.From != tt.wantFrom || call.Value.Int64() != tt.wantValue {
+				t.Fatalf("findEmittingCall() = %s %s, want %s %d", call.From.Hex(), call.Value, tt.wantFrom.Hex(), tt.wantValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package handler
+
+import (
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"strconv"
+)
+
+const (
+	// checkpointKey is the key of the last processed block in the checkpoint store.
+	checkpointKey = "evm:last_block"
+	// nativeCheckpointKey is the key of the last block scanned for native transfers in the checkpoint store.
+	nativeCheckpointKey = "evm:native_scanned_block"
+)
+
+// getLastProcessedBlock returns the last processed block, loading it from the checkpoint store on first use.
+//
+// Returns:
+// - uint64: the last processed block, 0 if there is no checkpoint.
+// - error: an error if the checkpoint cannot be loaded.
+func (h *EventHandler) getLastProcessedBlock() (uint64, error) {
+	h.lastBlockMutex.Lock()
+	defer h.lastBlockMutex.Unlock()
+
+	if h.lastProcessedBlock != 0 {
+		return h.lastProcessedBlock, nil
+	}
+
+	value, err := h.checkpoints.GetCheckpoint(h.getContext(), h.chainConfig.ChainID, checkpointKey)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get checkpoint")
+	}
+
+	if value == "" {
+		return 0, nil
+	}
+
+	block, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid checkpoint %q", value)
+	}
+
+	h.lastProcessedBlock = block
+	return block, nil
+}
+
+// saveCheckpoint records the block as processed and persists it. The checkpoint never moves backwards,
+// and the persisted value stays below the events waiting for confirmation or delivery so they are found again
+// after a restart.
+//
+// Parameters:
+// - block: the block up to which all events were processed.
+//
+// Returns:
+// - error: an error if the checkpoint cannot be saved.
+func (h *EventHandler) saveCheckpoint(block uint64) error {
+	h.lastBlockMutex.Lock()
+	defer h.lastBlockMutex.Unlock()
+
+	if block <= h.lastProcessedBlock {
+		return nil
+	}
+
+	persisted := block
+	if oldest, ok := h.undeliveredBlock(); ok && oldest <= persisted {
+		persisted = oldest - 1
+	}
+
+	if err := h.checkpoints.SaveCheckpoint(h.getContext(), h.chainConfig.ChainID, checkpointKey, strconv.FormatUint(persisted, 10)); err != nil {
+		return errors.Wrap(err, "failed to save checkpoint")
+	}
+
+	h.lastProcessedBlock = block
+	return nil
+}
+
+// undeliveredBlock returns the lowest block of the events not delivered yet, waiting for confirmation
+// or queued in memory.
+//
+// Returns:
+// - uint64: the block number.
+// - bool: false if every event was delivered.
+func (h *EventHandler) undeliveredBlock() (uint64, bool) {
+	oldest, found := h.pending.oldestBlock()
+	if queued, ok := h.queue.oldestBlock(); ok && (!found || queued < oldest) {
+		oldest, found = queued, true
+	}
+	return oldest, found
+}
+
+// rewindCheckpoint moves the checkpoint and the native scan cursor back to a block,
+// so the blocks after it are processed again.
+//
+// Parameters:
+// - block: the last block to keep as processed.
+//
+// Returns:
+// - error: an error if the checkpoint cannot be saved.
+func (h *EventHandler) rewindCheckpoint(block uint64) error {
+	h.lastBlockMutex.Lock()
+	defer h.lastBlockMutex.Unlock()
+
+	if block < h.nativeScannedBlock {
+		if err := h.checkpoints.SaveCheckpoint(h.getContext(), h.chainConfig.ChainID, nativeCheckpointKey, strconv.FormatUint(block, 10)); err != nil {
+			return errors.Wrap(err, "failed to save native scan checkpoint")
+		}
+		h.nativeScannedBlock = block
+	}
+
+	if block >= h.lastProcessedBlock {
+		return nil
+	}
+
+	if err := h.checkpoints.SaveCheckpoint(h.getContext(), h.chainConfig.ChainID, checkpointKey, strconv.FormatUint(block, 10)); err != nil {
+		return errors.Wrap(err, "failed to save checkpoint")
+	}
+
+	h.lastProcessedBlock = block
+	return nil
+}
+
+// getNativeScannedBlock returns the last block scanned for native transfers, loading it from the checkpoint store
+// on first use.
+//
+// Returns:
+// - uint64: the last scanned block, 0 if there is no checkpoint.
+// - error: an error if the checkpoint cannot be loaded.
+func (h *EventHandler) getNativeScannedBlock() (uint64, error) {
+	h.lastBlockMutex.Lock()
+	defer h.lastBlockMutex.Unlock()
+
+	if h.nativeScannedBlock != 0 {
+		return h.nativeScannedBlock, nil
+	}
+
+	value, err := h.checkpoints.GetCheckpoint(h.getContext(), h.chainConfig.ChainID, nativeCheckpointKey)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get native scan checkpoint")
+	}
+
+	if value == "" {
+		return 0, nil
+	}
+
+	block, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid native scan checkpoint %q", value)
+	}
+
+	h.nativeScannedBlock = block
+	return block, nil
+}
+
+// saveNativeScannedBlock records the block as scanned for native transfers and persists it.
+// Like the event checkpoint, the persisted value stays below the events waiting for confirmation or delivery.
+//
+// Parameters:
+// - block: the block up to which all blocks were scanned.
+//
+// Returns:
+// - error: an error if the checkpoint cannot be saved.
+func (h *EventHandler) saveNativeScannedBlock(block uint64) error {
+	h.lastBlockMutex.Lock()
+	defer h.lastBlockMutex.Unlock()
+
+	if block <= h.nativeScannedBlock {
+		return nil
+	}
+
+	persisted := block
+	if oldest, ok := h.undeliveredBlock(); ok && oldest <= persisted {
+		persisted = oldest - 1
+	}
+
+	if err := h.checkpoints.SaveCheckpoint(h.getContext(), h.chainConfig.ChainID, nativeCheckpointKey, strconv.FormatUint(persisted, 10)); err != nil {
+		return errors.Wrap(err, "failed to save native scan checkpoint")
+	}
+
+	h.nativeScannedBlock = block
+	return nil
+}
+
+// advanceLiveCheckpoint moves the checkpoint forward from the live subscriptions. It is a no-op while the
+// backfill has not reached the block the subscriptions started at, and it stays below the blocks of live events
+// that failed to process, so the checkpoint never skips unprocessed blocks.
+//
+// Parameters:
+// - block: the block up to which all live events were processed.
+func (h *EventHandler) advanceLiveCheckpoint(block uint64) {
+	h.lastBlockMutex.RLock()
+	caughtUp := h.liveFromBlock > 0 && h.lastProcessedBlock+1 >= h.liveFromBlock
+	h.lastBlockMutex.RUnlock()
+
+	if failed, ok := h.lowestFailedLiveBlock(); ok && failed <= block {
+		block = failed - 1
+	}
+
+	if !caughtUp {
+		return
+	}
+
+	if err := h.saveCheckpoint(block); err != nil {
+		h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to save checkpoint")
+	}
+}
+
+// backfill processes the blocks after the checkpoint up to toBlock through processBlockRange,
+// in ranges of maxBlockRange, saving the checkpoint after each range. Without a checkpoint it starts at toBlock.
+//
+// Parameters:
+// - toBlock: the last block to process.
+//
+// Returns:
+// - error: an error if the checkpoint cannot be loaded or saved, or if a block range cannot be processed.
+// The checkpoint is saved below the lowest failed log of the range in that case.
+func (h *EventHandler) backfill(toBlock uint64) error {
+	h.backfillMutex.Lock()
+	defer h.backfillMutex.Unlock()
+
+	fromBlock, err := h.getLastProcessedBlock()
+	if err != nil {
+		return err
+	}
+
+	if fromBlock == 0 {
+		h.recordBlockHash(toBlock)
+		return h.saveCheckpoint(toBlock)
+	}
+
+	// A live event may fail after the checkpoint passed its block.
+	if failed, ok := h.lowestFailedLiveBlock(); ok && failed <= fromBlock {
+		if err := h.rewindCheckpoint(failed - 1); err != nil {
+			return err
+		}
+		fromBlock = failed - 1
+	}
+
+	if toBlock > fromBlock+maxBlockRange {
+		h.logger.WithFields(logrus.Fields{
+			"chain":     h.chainConfig.Name,
+			"fromBlock": fromBlock + 1,
+			"toBlock":   toBlock,
+		}).Info("Backfilling events from checkpoint")
+	}
+
+	for fromBlock < toBlock {
+		if err := h.getContext().Err(); err != nil {
+			return errors.Wrap(err, "backfill cancelled")
+		}
+
+		endBlock := fromBlock + maxBlockRange
+		if endBlock > toBlock {
+			endBlock = toBlock
+		}
+
+		if err := h.processBlockRange(fromBlock+1, endBlock); err != nil {
+			// The blocks before the failed logs are processed, the next backfill resumes at the failed block.
+			var failed *failedLogsError
+			if errors.As(err, &failed) && failed.block > fromBlock+1 {
+				if saveErr := h.saveCheckpoint(failed.block - 1); saveErr != nil {
+					h.logger.WithField("chain", h.chainConfig.Name).WithError(saveErr).Error("Failed to save checkpoint")
+				}
+			}
+			return errors.Wrap(err, "failed to process block range")
+		}
+		h.clearFailedLiveBlocks(endBlock)
+
+		if endBlock == toBlock {
+			h.recordBlockHash(endBlock)
+		}
+
+		if err := h.saveCheckpoint(endBlock); err != nil {
+			return err
+		}
+
+		fromBlock = endBlock
+	}
+
+	return nil
+}
+
+// holdLiveCheckpoint keeps the checkpoint below the block of a live event that failed to process
+// until a backfill processes the block again.
+//
+// Parameters:
+// - block: the block of the failed event.
+func (h *EventHandler) holdLiveCheckpoint(block uint64) {
+	h.lastBlockMutex.Lock()
+	defer h.lastBlockMutex.Unlock()
+
+	if h.failedLiveBlocks == nil {
+		h.failedLiveBlocks = make(map[uint64]struct{})
+	}
+	h.failedLiveBlocks[block] = struct{}{}
+}
+
+// clearFailedLiveBlocks releases the hold of the failed live events a backfill processed again.
+//
+// Parameters:
+// - block: the last block processed by the backfill.
+func (h *EventHandler) clearFailedLiveBlocks(block uint64) {
+	h.lastBlockMutex.Lock()
+	defer h.lastBlockMutex.Unlock()
+
+	for failed := range h.failedLiveBlocks {
+		if failed <= block {
+			delete(h.failedLiveBlocks, failed)
+		}
+	}
+}
+
+// lowestFailedLiveBlock returns the lowest block of the live events that failed to process.
+//
+// Returns:
+// - uint64: the block number.
+// - bool: false if no live event failed.
+func (h *EventHandler) lowestFailedLiveBlock() (uint64, bool) {
+	h.lastBlockMutex.RLock()
+	defer h.lastBlockMutex.RUnlock()
+
+	var lowest uint64
+	found := false
+	for block := range h.failedLiveBlocks {
+		if !found || block < lowest {
+			lowest = block
+			found = true
+		}
+	}
+	return lowest, found
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+	"github.com/ClipFinance/relay-lib/common/checkpoint"
+	relaytypes "github.com/ClipFinance/relay-lib/common/types"
+	"testing"
+)
+
+func TestAdvanceLiveCheckpointHeldByFailedEvents(t *testing.T) {
+	config := &relaytypes.ChainConfig{ChainID: 1, Name: "test"}
+	queue, err := newEventQueue(config)
+	if err != nil {
+		t.Fatalf("newEventQueue() error = %v", err)
+	}
+
+	h := &EventHandler{
+		ctx:                context.Background(),
+		cancel:             func() {},
+		chainConfig:        config,
+		checkpoints:        checkpoint.NewMemoryStore(),
+		pending:            newPendingEvents(),
+		queue:              queue,
+		lastProcessedBlock: 100,
+		liveFromBlock:      100,
+	}
+
+	persisted := func() string {
+		t.Helper()
+
+		value, err := h.checkpoints.GetCheckpoint(context.Background(), config.ChainID, checkpointKey)
+		if err != nil {
+			t.Fatalf("GetCheckpoint() error = %v", err)
+		}
+		return value
+	}
+
+	tests := []struct {
+		name    string
+		failed  []uint64
+		cleared uint64
+		advance uint64
+		want    string
+	}{
+		{name: "no failed event", advance: 105, want: "105"},
+		{name: "failed event", failed: []uint64{108}, advance: 110, want: "107"},
+		{name: "lower failed event", failed: []uint64{109, 107}, advance: 112, want: "107"},
+		{name: "failed events partly processed again", cleared: 108, advance: 112, want: "108"},
+		{name: "failed events processed again", cleared: 109, advance: 112, want: "112"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, block := range tt.failed {
+				h.holdLiveCheckpoint(block)
+			}
+			if tt.cleared > 0 {
+				h.clearFailedLiveBlocks(tt.cleared)
+			}
+
+			h.advanceLiveCheckpoint(tt.advance)
+			if got := persisted(); got != tt.want {
+				t.Fatalf("checkpoint = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"github.com/ClipFinance/relay-lib/chains/evm/utils"
+	relaytypes "github.com/ClipFinance/relay-lib/common/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/sirupsen/logrus"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+)
+
+// confirmationCheckInterval is the interval between checks of the buffered events for confirmation.
+const confirmationCheckInterval = 5 * time.Second
+
+// pendingEvents buffers events until their blocks are confirmed, ordered by block number.
+type pendingEvents struct {
+	mutex  sync.Mutex              // Mutex for the buffered events.
+	events []relaytypes.ChainEvent // Buffered events ordered by block number.
+	keys   map[string]struct{}     // Keys of the buffered events.
+}
+
+// newPendingEvents creates an empty event buffer.
+//
+// Returns:
+// - *pendingEvents: a new pendingEvents instance.
+func newPendingEvents() *pendingEvents {
+	return &pendingEvents{keys: make(map[string]struct{})}
+}
+
+// add buffers an event unless an event of the same log is already buffered.
+//
+// Parameters:
+// - event: the event to buffer.
+//
+// Returns:
+// - bool: false if the event was already buffered.
+func (p *pendingEvents) add(event relaytypes.ChainEvent) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	key := eventKey(event)
+	if _, ok := p.keys[key]; ok {
+		return false
+	}
+	p.keys[key] = struct{}{}
+
+	index := sort.Search(len(p.events), func(i int) bool { return p.events[i].BlockNumber > event.BlockNumber })
+	p.events = append(p.events, relaytypes.ChainEvent{})
+	copy(p.events[index+1:], p.events[index:])
+	p.events[index] = event
+
+	return true
+}
+
+// remove drops the buffered event with the given key.
+//
+// Parameters:
+// - key: the event key.
+//
+// Returns:
+// - bool: false if no event with the key was buffered.
+func (p *pendingEvents) remove(key string) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if _, ok := p.keys[key]; !ok {
+		return false
+	}
+	delete(p.keys, key)
+
+	for i, event := range p.events {
+		if eventKey(event) == key {
+			p.events = append(p.events[:i], p.events[i+1:]...)
+			break
+		}
+	}
+
+	return true
+}
+
+// oldestBlock returns the block number of the oldest buffered event.
+//
+// Returns:
+// - uint64: the block number.
+// - bool: false if no event is buffered.
+func (p *pendingEvents) oldestBlock() (uint64, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if len(p.events) == 0 {
+		return 0, false
+	}
+	return p.events[0].BlockNumber, true
+}
+
+// snapshot returns a copy of the buffered events.
+//
+// Returns:
+// - []relaytypes.ChainEvent: the buffered events ordered by block number.
+func (p *pendingEvents) snapshot() []relaytypes.ChainEvent {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return append([]relaytypes.ChainEvent(nil), p.events...)
+}
+
+// eventKey identifies the log, or the call of a native transfer, an event was created from.
+func eventKey(event relaytypes.ChainEvent) string {
+	var logIndex uint
+	if metadata, ok := event.Metadata.(utils.EvmMetadata); ok {
+		if metadata.EventType == utils.EventTypeNativeTransfer {
+			return fmt.Sprintf("%s:native:%s:%s", event.TransactionHash, metadata.TraceAddress, event.BlockHash)
+		}
+		logIndex = metadata.LogIndex
+	}
+	return fmt.Sprintf("%s:%d:%s", event.TransactionHash, logIndex, event.BlockHash)
+}
+
+// logKey identifies a log the same way eventKey identifies its event.
+func logKey(log ethtypes.Log) string {
+	return fmt.Sprintf("%s:%d:%s", log.TxHash.String(), log.Index, log.BlockHash.String())
+}
+
+// emitEvent sends an event to the event channel, or buffers it until its block is confirmed
+// if ChainConfig.ConfirmEvents is set.
+//
+// Parameters:
+// - event: the event to emit.
+func (h *EventHandler) emitEvent(event relaytypes.ChainEvent) {
+	if h.chainConfig.ConfirmEvents {
+		h.pending.add(event)
+		return
+	}
+
+	h.sendEvent(event)
+}
+
+// sendEvent queues an event for the event channel and records it for reorg detection.
+// Events already emitted for the same log and block are skipped.
+//
+// Parameters:
+// - event: the event to send.
+func (h *EventHandler) sendEvent(event relaytypes.ChainEvent) {
+	if !h.dedup.add(eventKey(event)) {
+		h.logger.WithFields(logrus.Fields{
+			"chain":  h.chainConfig.Name,
+			"txHash": event.TransactionHash,
+			"block":  event.BlockNumber,
+		}).Debug("Skipping duplicate event")
+		return
+	}
+
+	h.enqueueEvent(event)
+	h.blocks.recordEvent(event)
+}
+
+// startConfirmationLoop starts releasing buffered events once, if ChainConfig.ConfirmEvents is set.
+// The loop runs until Stop and keeps its buffer across client updates.
+func (h *EventHandler) startConfirmationLoop() {
+	if !h.chainConfig.ConfirmEvents {
+		return
+	}
+
+	h.confirmationOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(confirmationCheckInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-h.stopChan:
+					return
+				case <-ticker.C:
+					h.releaseConfirmedEvents()
+				}
+			}
+		}()
+	})
+}
+
+// releaseConfirmedEvents sends the buffered events that are WaitNBlocks deep or below the finalized block.
+// Events whose block is no longer canonical are dropped.
+func (h *EventHandler) releaseConfirmedEvents() {
+	events := h.pending.snapshot()
+	if len(events) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	client := h.getClient()
+
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Warn("Failed to get block number for event confirmation")
+		return
+	}
+
+	// Not every chain supports the finalized tag, the confirmation depth applies alone then.
+	var finalized uint64
+	if header, err := client.HeaderByNumber(ctx, big.NewInt(int64(rpc.FinalizedBlockNumber))); err == nil {
+		finalized = header.Number.Uint64()
+	}
+
+	canonical := make(map[uint64]common.Hash)
+	for _, event := range events {
+		if event.BlockNumber+h.chainConfig.WaitNBlocks > head && event.BlockNumber > finalized {
+			// Events are ordered by block, the following ones are not confirmed either.
+			return
+		}
+
+		hash, ok := canonical[event.BlockNumber]
+		if !ok {
+			header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(event.BlockNumber))
+			if err != nil {
+				h.logger.WithFields(logrus.Fields{
+					"chain": h.chainConfig.Name,
+					"block": event.BlockNumber,
+				}).WithError(err).Warn("Failed to get block hash for event confirmation")
+				return
+			}
+			hash = header.Hash()
+			canonical[event.BlockNumber] = hash
+		}
+
+		if !h.pending.remove(eventKey(event)) {
+			continue
+		}
+
+		if hash != common.HexToHash(event.BlockHash) {
+			h.logger.WithFields(logrus.Fields{
+				"chain":     h.chainConfig.Name,
+				"txHash":    event.TransactionHash,
+				"blockHash": event.BlockHash,
+				"block":     event.BlockNumber,
+			}).Warn("Dropping unconfirmed event from orphaned block")
+			continue
+		}
+
+		h.sendEvent(event)
+	}
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"container/list"
+	"context"
+	relaytypes "github.com/ClipFinance/relay-lib/common/types"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+const (
+	// dedupTTL is the time an emitted event is remembered to skip duplicates.
+	dedupTTL = 24 * time.Hour
+	// maxDedupEntries is the maximum number of events remembered in memory.
+	maxDedupEntries = 10000
+)
+
+// dedupEntry is an event key remembered by the dedup cache.
+type dedupEntry struct {
+	key       string    // Event key.
+	expiresAt time.Time // Time after which the key is forgotten.
+}
+
+// dedupCache remembers recently emitted event keys for a bounded time, evicting the oldest keys when full.
+type dedupCache struct {
+	mutex   sync.Mutex               // Mutex for the cache.
+	entries map[string]*list.Element // Entries by event key.
+	order   *list.List               // Entries in insertion order, oldest first.
+	ttl     time.Duration            // Time an entry is kept.
+	maxSize int                      // Maximum number of entries.
+}
+
+// newDedupCache creates an empty dedup cache.
+//
+// Parameters:
+// - ttl: the time an entry is kept.
+// - maxSize: the maximum number of entries.
+//
+// Returns:
+// - *dedupCache: a new dedupCache instance.
+func newDedupCache(ttl time.Duration, maxSize int) *dedupCache {
+	return &dedupCache{
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		ttl:     ttl,
+		maxSize: maxSize,
+	}
+}
+
+// add remembers the key.
+//
+// Parameters:
+// - key: the event key.
+//
+// Returns:
+// - bool: false if the key is already remembered.
+func (c *dedupCache) add(key string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	c.evictExpired(now)
+
+	if _, ok := c.entries[key]; ok {
+		return false
+	}
+
+	c.entries[key] = c.order.PushBack(&dedupEntry{key: key, expiresAt: now.Add(c.ttl)})
+
+	for c.order.Len() > c.maxSize {
+		c.removeElement(c.order.Front())
+	}
+
+	return true
+}
+
+// contains reports whether the key is remembered.
+//
+// Parameters:
+// - key: the event key.
+//
+// Returns:
+// - bool: true if the key is remembered and not expired.
+func (c *dedupCache) contains(key string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[key]
+	return ok && time.Now().Before(element.Value.(*dedupEntry).expiresAt)
+}
+
+// remove forgets the key.
+//
+// Parameters:
+// - key: the event key.
+func (c *dedupCache) remove(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.removeElement(element)
+	}
+}
+
+// evictExpired removes the expired entries. The caller must hold the cache mutex.
+func (c *dedupCache) evictExpired(now time.Time) {
+	for element := c.order.Front(); element != nil; element = c.order.Front() {
+		if now.Before(element.Value.(*dedupEntry).expiresAt) {
+			return
+		}
+		c.removeElement(element)
+	}
+}
+
+// removeElement removes an entry. The caller must hold the cache mutex.
+func (c *dedupCache) removeElement(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*dedupEntry).key)
+}
+
+// markProcessed records an event as processed in ChainConfig.EventDedupStore if set. It is called when the event
+// is handed over to the application, so events dropped from the queue or still queued at shutdown are emitted
+// again after a restart. A store failure lets the event through, a duplicate is preferred over a lost deposit.
+//
+// Parameters:
+// - event: the event about to be delivered.
+//
+// Returns:
+// - bool: false if the event was already delivered, by this or a previous run.
+func (h *EventHandler) markProcessed(event relaytypes.ChainEvent) bool {
+	store := h.chainConfig.EventDedupStore
+	if store == nil {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	recorded, err := store.MarkEventProcessed(ctx, h.chainConfig.ChainID, eventKey(event), dedupTTL)
+	if err != nil {
+		h.logger.WithFields(logrus.Fields{
+			"chain":  h.chainConfig.Name,
+			"txHash": event.TransactionHash,
+		}).WithError(err).Warn("Failed to record processed event")
+		return true
+	}
+
+	return recorded
+}
+
+// forgetProcessed forgets an emitted event, so it is emitted again if its block becomes canonical again
+// or the event is found again.
+//
+// Parameters:
+// - event: the retracted, dropped or undelivered event.
+func (h *EventHandler) forgetProcessed(event relaytypes.ChainEvent) {
+	key := eventKey(event)
+	h.dedup.remove(key)
+
+	store := h.chainConfig.EventDedupStore
+	if store == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	if err := store.ForgetEvent(ctx, h.chainConfig.ChainID, key); err != nil {
+		h.logger.WithFields(logrus.Fields{
+			"chain":  h.chainConfig.Name,
+			"txHash": event.TransactionHash,
+		}).WithError(err).Warn("Failed to forget processed event")
+	}
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDedupCache(t *testing.T) {
+	tests := []struct {
+		name         string
+		ttl          time.Duration
+		maxSize      int
+		add          []string
+		wait         time.Duration
+		remove       []string
+		wantContains []string
+		wantMissing  []string
+	}{
+		{
+			name:         "duplicate",
+			ttl:          time.Hour,
+			maxSize:      10,
+			add:          []string{"a", "a"},
+			wantContains: []string{"a"},
+		},
+		{
+			name:        "expired",
+			ttl:         10 * time.Millisecond,
+			maxSize:     10,
+			add:         []string{"a", "b"},
+			wait:        20 * time.Millisecond,
+			wantMissing: []string{"a", "b"},
+		},
+		{
+			name:         "oldest evicted when full",
+			ttl:          time.Hour,
+			maxSize:      2,
+			add:          []string{"a", "b", "c"},
+			wantContains: []string{"b", "c"},
+			wantMissing:  []string{"a"},
+		},
+		{
+			name:         "removed",
+			ttl:          time.Hour,
+			maxSize:      10,
+			add:          []string{"a", "b"},
+			remove:       []string{"a", "unknown"},
+			wantContains: []string{"b"},
+			wantMissing:  []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newDedupCache(tt.ttl, tt.maxSize)
+
+			seen := make(map[string]bool)
+			for _, key := range tt.add {
+				if added := c.add(key); added == seen[key] {
+					t.Fatalf("add(%s) = %v, want %v", key, added, !seen[key])
+				}
+				seen[key] = true
+			}
+
+			time.Sleep(tt.wait)
+
+			for _, key := range tt.remove {
+				c.remove(key)
+			}
+
+			for _, key := range tt.wantContains {
+				if !c.contains(key) {
+					t.Fatalf("contains(%s) = false", key)
+				}
+			}
+			for _, key := range tt.wantMissing {
+				if c.contains(key) {
+					t.Fatalf("contains(%s) = true", key)
+				}
+			}
+
+			// A forgotten key is added again.
+			for _, key := range tt.wantMissing {
+				if !c.add(key) {
+					t.Fatalf("add(%s) = false after it was forgotten", key)
+				}
+			}
+		})
+	}
+}
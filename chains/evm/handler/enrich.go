@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"sync"
+)
+
+const (
+	// rpcBatchSize is the maximum number of calls in a JSON-RPC batch request.
+	rpcBatchSize = 100
+	// enrichWorkers is the number of batch requests sent concurrently.
+	enrichWorkers = 4
+	// maxHeaderCacheEntries is the maximum number of block headers cached.
+	maxHeaderCacheEntries = 2048
+)
+
+// headerCache caches block headers by block number.
+type headerCache struct {
+	mutex   sync.Mutex                  // Mutex for the headers.
+	headers map[uint64]*ethtypes.Header // Cached headers by block number.
+}
+
+// newHeaderCache creates an empty header cache.
+//
+// Returns:
+// - *headerCache: a new headerCache instance.
+func newHeaderCache() *headerCache {
+	return &headerCache{headers: make(map[uint64]*ethtypes.Header)}
+}
+
+// get returns the cached header of a block if it has the given hash.
+//
+// Parameters:
+// - number: the block number.
+// - hash: the expected block hash.
+//
+// Returns:
+// - *ethtypes.Header: the header, or nil if it is not cached or was replaced by a reorganization.
+func (c *headerCache) get(number uint64, hash common.Hash) *ethtypes.Header {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	header, ok := c.headers[number]
+	if !ok || header.Hash() != hash {
+		return nil
+	}
+	return header
+}
+
+// add caches a header, evicting the lowest block when the cache is full.
+//
+// Parameters:
+// - header: the header to cache.
+func (c *headerCache) add(header *ethtypes.Header) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	number := header.Number.Uint64()
+	if _, ok := c.headers[number]; !ok && len(c.headers) >= maxHeaderCacheEntries {
+		lowest := number
+		for cached := range c.headers {
+			if cached < lowest {
+				lowest = cached
+			}
+		}
+		if lowest == number {
+			return
+		}
+		delete(c.headers, lowest)
+	}
+
+	c.headers[number] = header
+}
+
+// logDetails holds the data a log is turned into an event with.
+type logDetails struct {
+	txs      map[common.Hash]*ethtypes.Transaction // Transactions by hash.
+	receipts map[common.Hash]*ethtypes.Receipt     // Receipts by transaction hash.
+	headers  map[uint64]*ethtypes.Header           // Headers by block number.
+	errs     map[common.Hash]error                 // Lookup errors by transaction hash.
+}
+
+// enrichLogs fetches the transactions, receipts and block headers of logs with batched JSON-RPC requests,
+// sent by a bounded pool of workers. Headers are served from the header cache where possible.
+//
+// Parameters:
+// - logs: the logs to enrich.
+//
+// Returns:
+// - *logDetails: the fetched data, with the errors of the individual lookups.
+// - error: an error if a batch request fails.
+func (h *EventHandler) enrichLogs(logs []ethtypes.Log) (*logDetails, error) {
+	details := &logDetails{
+		txs:      make(map[common.Hash]*ethtypes.Transaction),
+		receipts: make(map[common.Hash]*ethtypes.Receipt),
+		headers:  make(map[uint64]*ethtypes.Header),
+		errs:     make(map[common.Hash]error),
+	}
+
+	var calls []rpc.BatchElem
+	txs := make(map[common.Hash]**ethtypes.Transaction)
+	receipts := make(map[common.Hash]**ethtypes.Receipt)
+	headers := make(map[uint64]**ethtypes.Header)
+
+	for _, log := range logs {
+		if _, ok := txs[log.TxHash]; !ok {
+			tx := new(*ethtypes.Transaction)
+			receipt := new(*ethtypes.Receipt)
+			txs[log.TxHash] = tx
+			receipts[log.TxHash] = receipt
+			calls = append(calls,
+				rpc.BatchElem{Method: "eth_getTransactionByHash", Args: []interface{}{log.TxHash}, Result: tx},
+				rpc.BatchElem{Method: "eth_getTransactionReceipt", Args: []interface{}{log.TxHash}, Result: receipt},
+			)
+		}
+
+		if _, ok := details.headers[log.BlockNumber]; ok {
+			continue
+		}
+		if header := h.headers.get(log.BlockNumber, log.BlockHash); header != nil {
+			details.headers[log.BlockNumber] = header
+			continue
+		}
+		if _, ok := headers[log.BlockNumber]; !ok {
+			header := new(*ethtypes.Header)
+			headers[log.BlockNumber] = header
+			calls = append(calls, rpc.BatchElem{
+				Method: "eth_getBlockByNumber",
+				Args:   []interface{}{hexutil.EncodeUint64(log.BlockNumber), false},
+				Result: header,
+			})
+		}
+	}
+
+	if err := h.batchCall(calls); err != nil {
+		return nil, err
+	}
+
+	for _, call := range calls {
+		if call.Error == nil {
+			continue
+		}
+		if hash, ok := call.Args[0].(common.Hash); ok {
+			details.errs[hash] = errors.Wrapf(call.Error, "failed to call %s", call.Method)
+		}
+	}
+
+	for hash, tx := range txs {
+		if *tx == nil {
+			if details.errs[hash] == nil {
+				details.errs[hash] = errors.New("transaction not found")
+			}
+			continue
+		}
+		details.txs[hash] = *tx
+
+		if *receipts[hash] == nil {
+			if details.errs[hash] == nil {
+				details.errs[hash] = errors.New("transaction receipt not found")
+			}
+			continue
+		}
+		details.receipts[hash] = *receipts[hash]
+	}
+
+	for number, header := range headers {
+		if *header == nil {
+			continue
+		}
+		details.headers[number] = *header
+		h.headers.add(*header)
+	}
+
+	return details, nil
+}
+
+// batchCall sends the calls in batches of rpcBatchSize, enrichWorkers batches at a time.
+// The errors of the individual calls are set on the calls.
+//
+// Parameters:
+// - calls: the calls to send.
+//
+// Returns:
+// - error: an error if a batch request fails.
+func (h *EventHandler) batchCall(calls []rpc.BatchElem) error {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	batches := make(chan []rpc.BatchElem)
+	errs := make(chan error, enrichWorkers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < enrichWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				if err := h.getClient().Client().BatchCallContext(h.getContext(), batch); err != nil {
+					select {
+					case errs <- err:
+					default:
+					}
+				}
+			}
+		}()
+	}
+
+	for start := 0; start < len(calls); start += rpcBatchSize {
+		end := start + rpcBatchSize
+		if end > len(calls) {
+			end = len(calls)
+		}
+		batches <- calls[start:end]
+	}
+	close(batches)
+	wg.Wait()
+
+	select {
+	case err := <-errs:
+		return errors.Wrap(err, "failed to send batch request")
+	default:
+		return nil
+	}
+}
@@ -2,13 +2,14 @@ package handler
 
 import (
 	"context"
+	"fmt"
 	"github.com/ClipFinance/relay-lib/chains/evm/utils"
+	"github.com/ClipFinance/relay-lib/common/checkpoint"
 	relaytypes "github.com/ClipFinance/relay-lib/common/types"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	"math/big"
 	"sync"
 	"time"
 )
@@ -19,6 +20,7 @@ const (
 	reconnectTimeout     = 5 * time.Second  // Timeout for reconnect attempts.
 	retryTimeout         = 5 * time.Minute  // Timeout for retry operations.
 	maxReconnectAttempts = 3                // Maximum number of reconnect attempts.
+	checkpointInterval   = time.Minute      // Interval for saving the checkpoint while subscribed.
 )
 
 // EventHandler handles chain events with thread-safe access.
@@ -29,13 +31,32 @@ type EventHandler struct {
 	chainConfig          *relaytypes.ChainConfig    // Chain configuration.
 	logger               *logrus.Logger             // Logger for logging events.
 	client               *ethclient.Client          // Ethereum client.
+	clientMutex          sync.RWMutex               // Mutex for the client and its context.
 	solverAddress        string                     // Solver address.
 	eventChan            chan relaytypes.ChainEvent // Channel for chain events.
 	relaySubscription    *Subscription              // Subscription for relay events.
 	transferSubscription *Subscription              // Subscription for transfer events.
+	watchList            *utils.WatchList           // Watched events and their decoding rules.
+	checkpoints          relaytypes.CheckpointStore // Store for the last processed block.
 	lastProcessedBlock   uint64                     // Last processed block number.
-	lastBlockMutex       sync.RWMutex               // Mutex for last processed block.
+	liveFromBlock        uint64                     // First block delivered by the current subscriptions.
+	nativeScannedBlock   uint64                     // Last block scanned for native transfers.
+	failedLiveBlocks     map[uint64]struct{}        // Blocks of live events that failed to process, the checkpoint stays below them.
+	tracer               string                     // Block trace method supported by the node, empty until detected.
+	lastBlockMutex       sync.RWMutex               // Mutex for the block markers and the trace method.
+	backfillMutex        sync.Mutex                 // Mutex serializing backfills and checkpoint rewinds.
+	blocks               *blockWindow               // Recent block hashes and emitted events for reorg detection.
+	pending              *pendingEvents             // Events waiting for confirmation, kept across client updates.
+	dedup                *dedupCache                // Recently emitted events.
+	headers              *headerCache               // Headers of the blocks of recent logs.
+	queue                *eventQueue                // Events waiting for delivery to the event channel.
+	confirmationOnce     sync.Once                  // Guard for starting the confirmation loop.
+	stopChan             chan struct{}              // Channel closed when the handler is stopped.
+	stopOnce             sync.Once                  // Guard for closing the stop channel.
 	pollingTicker        *time.Ticker               // Ticker for polling.
+	pollingStop          chan struct{}              // Channel closed to stop the polling goroutine.
+	fallback             bool                       // Whether events are polled because the subscriptions failed.
+	modeMutex            sync.Mutex                 // Mutex for polling ticker, polling stop and fallback.
 }
 
 // NewEventHandler creates a new event handler instance.
@@ -59,6 +80,25 @@ func NewEventHandler(
 	solverAddr string,
 	eventChan chan relaytypes.ChainEvent,
 ) (*EventHandler, error) {
+	checkpoints := config.CheckpointStore
+	if checkpoints == nil {
+		checkpoints = checkpoint.NewMemoryStore()
+	}
+
+	watched := config.WatchList
+	if len(watched) == 0 {
+		watched = utils.DefaultWatchList(config.RelayReceiver)
+	}
+	watchList, err := utils.NewWatchList(watched)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build watch list")
+	}
+
+	queue, err := newEventQueue(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create event queue")
+	}
+
 	handlerCtx, cancel := context.WithCancel(ctx)
 
 	handler := &EventHandler{
@@ -69,8 +109,17 @@ func NewEventHandler(
 		client:               client,
 		solverAddress:        solverAddr,
 		eventChan:            eventChan,
+		checkpoints:          checkpoints,
+		blocks:               newBlockWindow(),
+		pending:              newPendingEvents(),
+		dedup:                newDedupCache(dedupTTL, maxDedupEntries),
+		headers:              newHeaderCache(),
+		failedLiveBlocks:     make(map[uint64]struct{}),
+		queue:                queue,
+		stopChan:             make(chan struct{}),
 		relaySubscription:    &Subscription{},
 		transferSubscription: &Subscription{},
+		watchList:            watchList,
 	}
 
 	return handler, nil
@@ -81,7 +130,7 @@ func NewEventHandler(
 // Parameters:
 // - client: the new Ethereum client.
 func (h *EventHandler) UpdateClient(client *ethclient.Client) {
-	h.cancel()
+	h.cancelContext()
 
 	if h.relaySubscription != nil {
 		h.relaySubscription.Close()
@@ -93,13 +142,18 @@ func (h *EventHandler) UpdateClient(client *ethclient.Client) {
 	}
 
 	handlerCtx, cancel := context.WithCancel(context.Background())
+
+	h.clientMutex.Lock()
 	h.ctx = handlerCtx
 	h.cancel = cancel
-
 	h.client = client
+	h.clientMutex.Unlock()
 
-	if h.pollingTicker != nil {
-		h.pollingTicker.Stop()
+	// The new endpoint may support other trace methods.
+	h.setTracer("")
+
+	if h.isPolling() {
+		h.stopPolling()
 		if err := h.StartHTTPPolling(); err != nil {
 			h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to restart HTTP polling after client update")
 		}
@@ -108,77 +162,190 @@ func (h *EventHandler) UpdateClient(client *ethclient.Client) {
 
 	if err := h.setupSubscriptions(h.solverAddress); err != nil {
 		h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to setup subscriptions after client update")
+		h.fallbackToPolling()
 	}
 }
 
 // Stop stops the event handler and closes subscriptions and polling.
 func (h *EventHandler) Stop() {
-	h.cancel()
+	h.cancelContext()
+	h.stopOnce.Do(func() { close(h.stopChan) })
 	if h.relaySubscription != nil {
 		h.relaySubscription.Close()
 	}
 	if h.transferSubscription != nil {
 		h.transferSubscription.Close()
 	}
-	if h.pollingTicker != nil {
-		h.pollingTicker.Stop()
-	}
+	h.stopPolling()
 }
 
-// processEvent processes a single event log and sends it to the event channel.
+// getClient returns the current Ethereum client.
+func (h *EventHandler) getClient() *ethclient.Client {
+	h.clientMutex.RLock()
+	defer h.clientMutex.RUnlock()
+	return h.client
+}
+
+// getContext returns the context of the current client, canceled when the client is replaced or the handler stops.
+func (h *EventHandler) getContext() context.Context {
+	h.clientMutex.RLock()
+	defer h.clientMutex.RUnlock()
+	return h.ctx
+}
+
+// cancelContext cancels the context of the current client.
+func (h *EventHandler) cancelContext() {
+	h.clientMutex.RLock()
+	defer h.clientMutex.RUnlock()
+	h.cancel()
+}
+
+// processEvent processes a single event log and emits it.
 //
 // Parameters:
-// - eventType: the type of the event (e.g., "relay", "transfer").
 // - log: the event log to process.
 //
 // Returns:
 // - error: an error if any issue occurs during event processing.
-func (h *EventHandler) processEvent(eventType string, log ethtypes.Log) error {
-	tx, _, err := h.client.TransactionByHash(h.ctx, log.TxHash)
+func (h *EventHandler) processEvent(log ethtypes.Log) error {
+	// Replayed logs of already emitted events are skipped before any lookups.
+	if h.dedup.contains(logKey(log)) {
+		return nil
+	}
+
+	details, err := h.enrichLogs([]ethtypes.Log{log})
 	if err != nil {
-		return errors.Wrap(err, "failed to get transaction by hash")
+		return errors.Wrap(err, "failed to get event details")
+	}
+
+	return h.emitLog(log, details)
+}
+
+// failedLogsError is the error of a block range holding logs that could not be processed.
+// The range is processed again from the lowest failed block, already emitted logs are skipped then.
+type failedLogsError struct {
+	block uint64 // Lowest block of the failed logs.
+	err   error  // Error of the first failed log.
+}
+
+// Error returns the error of the first failed log.
+func (e *failedLogsError) Error() string {
+	return fmt.Sprintf("failed to process logs from block %d: %s", e.block, e.err)
+}
+
+// Unwrap returns the error of the first failed log.
+func (e *failedLogsError) Unwrap() error {
+	return e.err
+}
+
+// processLogs processes the event logs of a block range and emits them, fetching the details of all logs
+// with batched requests. Logs that cannot be processed are logged and the remaining logs are still emitted.
+//
+// Parameters:
+// - logs: the event logs to process, in chain order.
+//
+// Returns:
+// - error: an error if the details of the logs cannot be fetched, a *failedLogsError if any log cannot be processed.
+func (h *EventHandler) processLogs(logs []ethtypes.Log) error {
+	// Replayed logs of already emitted events are skipped before any lookups.
+	pending := make([]ethtypes.Log, 0, len(logs))
+	for _, log := range logs {
+		if !h.dedup.contains(logKey(log)) {
+			pending = append(pending, log)
+		}
 	}
 
-	// Get Tx Sender
-	signer := ethtypes.LatestSignerForChainID(tx.ChainId())
-	fromAddress, err := ethtypes.Sender(signer, tx)
+	if len(pending) == 0 {
+		return nil
+	}
+
+	details, err := h.enrichLogs(pending)
 	if err != nil {
-		return errors.Wrap(err, "failed to get transaction sender")
+		return errors.Wrap(err, "failed to get event details")
 	}
 
-	var quoteId string
-	var amount string
+	var failed *failedLogsError
+	for _, log := range pending {
+		if err := h.emitLog(log, details); err != nil {
+			h.logger.WithFields(logrus.Fields{
+				"chain":     h.chainConfig.Name,
+				"eventType": h.watchList.EventType(log),
+				"txHash":    log.TxHash.Hex(),
+				"block":     log.BlockNumber,
+			}).WithError(err).Error("Failed to process log")
 
-	switch eventType {
-	case "FundsForwardedWithData":
-		quoteId, err = utils.ExtractQuoteIDFromTxData(tx.Data())
-		if err != nil {
-			return errors.Wrap(err, "failed to extract quoteId from FundsForwardedWithData event")
+			if failed == nil {
+				failed = &failedLogsError{block: log.BlockNumber, err: err}
+			} else if log.BlockNumber < failed.block {
+				failed.block = log.BlockNumber
+			}
 		}
-		quoteId = "0x" + quoteId
-		amount = tx.Value().String()
+	}
 
-	case "FundsForwarded":
-		quoteId = string(tx.Data())
-		amount = tx.Value().String()
+	if failed != nil {
+		return failed
+	}
+
+	return nil
+}
 
-	case "Transfer":
-		input := tx.Data()
-		quoteId, err = utils.ExtractQuoteIDFromTxData(input)
+// emitLog turns an event log into an event and emits it.
+// The quote ID and the amount are extracted according to the watch list rules of the event.
+//
+// Parameters:
+// - log: the event log.
+// - details: the transactions, receipts and headers of the logs.
+//
+// Returns:
+// - error: an error if the details of the log are missing or the log cannot be decoded.
+func (h *EventHandler) emitLog(log ethtypes.Log, details *logDetails) error {
+	if err := details.errs[log.TxHash]; err != nil {
+		return err
+	}
+
+	tx := details.txs[log.TxHash]
+	receipt := details.receipts[log.TxHash]
+
+	// The transaction was included in another block since, reorg detection handles the orphaned log.
+	if receipt.BlockHash != log.BlockHash {
+		h.logger.WithFields(logrus.Fields{
+			"chain":     h.chainConfig.Name,
+			"txHash":    log.TxHash.Hex(),
+			"blockHash": log.BlockHash.Hex(),
+		}).Warn("Skipping log from non-canonical block")
+		return nil
+	}
+
+	// Events reading the amount from their call are attributed to the call that emitted them,
+	// which differs from the transaction for deposits made through smart wallets, multicalls or bundlers.
+	var call *utils.Call
+	if h.watchList.NeedsCall(log) {
+		var err error
+		call, err = h.emittingCall(log, tx, receipt)
 		if err != nil {
-			return errors.Wrap(err, "failed to extract quoteId from Transfer event")
+			return errors.Wrap(err, "failed to find the call of the event")
 		}
-		quoteId = "0x" + quoteId
+	}
 
-		amount = new(big.Int).SetBytes(log.Data).String()
+	decoded, err := h.watchList.Decode(log, tx, call)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode event")
+	}
 
-	default:
-		return errors.New("unknown event type: " + eventType)
+	// The depositor read from the event or its call, the transaction sender for events without one.
+	fromAddress := decoded.Depositor
+	if fromAddress == "" {
+		signer := ethtypes.LatestSignerForChainID(tx.ChainId())
+		sender, err := ethtypes.Sender(signer, tx)
+		if err != nil {
+			return errors.Wrap(err, "failed to get transaction sender")
+		}
+		fromAddress = sender.Hex()
 	}
 
-	block, err := h.client.HeaderByNumber(h.ctx, new(big.Int).SetUint64(log.BlockNumber))
-	if err != nil {
-		return errors.Wrap(err, "failed to get block time")
+	block, ok := details.headers[log.BlockNumber]
+	if !ok {
+		return errors.New("failed to get block time")
 	}
 
 	chainEvent := relaytypes.ChainEvent{
@@ -186,21 +353,21 @@ func (h *EventHandler) processEvent(eventType string, log ethtypes.Log) error {
 		BlockNumber:       log.BlockNumber,
 		BlockHash:         log.BlockHash.String(),
 		FromTokenAddr:     log.Address.String(),
-		FromAddress:       fromAddress.Hex(),
+		FromAddress:       fromAddress,
 		ToAddress:         tx.To().Hex(),
 		TransactionHash:   log.TxHash.String(),
-		QuoteID:           quoteId,
+		QuoteID:           decoded.QuoteID,
 		FromTxMinedAt:     time.Unix(int64(block.Time), 0),
-		TransactionAmount: amount,
+		TransactionAmount: decoded.Amount,
 		FromNonce:         tx.Nonce(),
 		Metadata: utils.EvmMetadata{
-			EventType: eventType,
+			EventType: decoded.EventType,
 			LogIndex:  log.Index,
 			Data:      log.Data,
 		},
 	}
 
-	h.eventChan <- chainEvent
+	h.emitEvent(chainEvent)
 
 	return nil
 }
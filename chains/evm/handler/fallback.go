@@ -0,0 +1,124 @@
+package handler
+
+import (
+	relaytypes "github.com/ClipFinance/relay-lib/common/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// errPollingFallback is returned by reconnectSubscription when the handler fell back to HTTP polling.
+var errPollingFallback = errors.New("subscriptions failed, fell back to HTTP polling")
+
+// isPolling reports whether HTTP polling is running.
+func (h *EventHandler) isPolling() bool {
+	h.modeMutex.Lock()
+	defer h.modeMutex.Unlock()
+
+	return h.pollingTicker != nil
+}
+
+// isFallback reports whether the handler polls because its subscriptions failed.
+func (h *EventHandler) isFallback() bool {
+	h.modeMutex.Lock()
+	defer h.modeMutex.Unlock()
+
+	return h.fallback
+}
+
+// stopPolling stops HTTP polling if it is running.
+func (h *EventHandler) stopPolling() {
+	h.modeMutex.Lock()
+	defer h.modeMutex.Unlock()
+
+	if h.pollingTicker == nil {
+		return
+	}
+
+	h.pollingTicker.Stop()
+	close(h.pollingStop)
+	h.pollingTicker = nil
+	h.pollingStop = nil
+}
+
+// fallbackToPolling replaces the failing subscriptions with HTTP polling, which continues from the last processed
+// block, and keeps trying to restore the subscriptions in the background.
+func (h *EventHandler) fallbackToPolling() {
+	h.modeMutex.Lock()
+	if h.fallback {
+		h.modeMutex.Unlock()
+		return
+	}
+	h.fallback = true
+	h.modeMutex.Unlock()
+
+	if h.relaySubscription != nil {
+		h.relaySubscription.Close()
+	}
+	if h.transferSubscription != nil {
+		h.transferSubscription.Close()
+	}
+
+	h.logger.WithField("chain", h.chainConfig.Name).Warn("Subscriptions keep failing, falling back to HTTP polling")
+
+	if err := h.StartHTTPPolling(); err != nil {
+		h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to start HTTP polling fallback")
+	}
+
+	h.notifyModeChange(relaytypes.WebSocketMode, relaytypes.HTTPPollingMode)
+
+	go h.recoverSubscriptions()
+}
+
+// recoverSubscriptions periodically tries to restore the subscriptions while polling as a fallback.
+// Once they are restored, polling stops and the subscriptions take over from the last processed block.
+func (h *EventHandler) recoverSubscriptions() {
+	ticker := time.NewTicker(retryTimeout)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.stopChan:
+			return
+
+		case <-ticker.C:
+			if !h.isFallback() {
+				return
+			}
+
+			if err := h.setupSubscriptions(h.solverAddress); err != nil {
+				h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Warn("Subscriptions not recovered yet, continuing HTTP polling")
+				continue
+			}
+
+			h.stopPolling()
+
+			h.modeMutex.Lock()
+			h.fallback = false
+			h.modeMutex.Unlock()
+
+			go h.handleEvents()
+
+			h.logger.WithField("chain", h.chainConfig.Name).Info("Subscriptions recovered, stopped HTTP polling fallback")
+			h.notifyModeChange(relaytypes.HTTPPollingMode, relaytypes.WebSocketMode)
+			return
+		}
+	}
+}
+
+// notifyModeChange calls ChainConfig.OnSubscriptionModeChange if set.
+//
+// Parameters:
+// - from: the previous mode.
+// - to: the new mode.
+func (h *EventHandler) notifyModeChange(from, to relaytypes.SubscriptionMode) {
+	h.logger.WithFields(logrus.Fields{
+		"chain": h.chainConfig.Name,
+		"from":  from.String(),
+		"to":    to.String(),
+	}).Info("Event listener mode changed")
+
+	if h.chainConfig.OnSubscriptionModeChange != nil {
+		h.chainConfig.OnSubscriptionModeChange(h.chainConfig.ChainID, from, to)
+	}
+}
@@ -1,14 +1,12 @@
 package handler
 
 import (
-	"github.com/ClipFinance/relay-lib/chains/evm/utils"
 	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"math/big"
+	"sort"
 	"time"
 )
 
@@ -19,25 +17,36 @@ const (
 	maxBlockRange = uint64(1000)
 )
 
-// StartHTTPPolling starts polling for FundsForwarded, FundsForwardedWithData, and Transfer events.
+// StartHTTPPolling starts polling for the watched events.
 // It initializes a ticker to poll at regular intervals and processes events in a separate goroutine.
 //
 // Returns:
 // - error: an error if any issue occurs during the polling setup.
 func (h *EventHandler) StartHTTPPolling() error {
-	h.pollingTicker = time.NewTicker(defaultPollingInterval)
+	ticker := time.NewTicker(defaultPollingInterval)
+	stop := make(chan struct{})
+
+	h.modeMutex.Lock()
+	h.pollingTicker = ticker
+	h.pollingStop = stop
+	h.modeMutex.Unlock()
+
+	h.startConfirmationLoop()
+	h.startDelivery()
 
 	h.logger.WithFields(logrus.Fields{
 		"chain":    h.chainConfig.Name,
 		"interval": defaultPollingInterval,
-	}).Info("Start polling FundsForwarded, FundsForwardedWithData and Transfer events")
+	}).Info("Start polling watched events")
 
 	go func() {
 		for {
 			select {
-			case <-h.ctx.Done():
+			case <-h.getContext().Done():
 				return
-			case <-h.pollingTicker.C:
+			case <-stop:
+				return
+			case <-ticker.C:
 				if err := h.pollEvents(); err != nil {
 					h.logger.WithError(err).Error("Error polling events")
 				}
@@ -48,50 +57,34 @@ func (h *EventHandler) StartHTTPPolling() error {
 	return nil
 }
 
-// pollEvents polls for FundsForwarded, FundsForwardedWithData, and Transfer events.
-// It retrieves the current block number and processes the block range for events.
+// pollEvents polls for the watched events.
+// It checks the processed blocks for a reorganization, retrieves the current block number
+// and processes the blocks after the checkpoint up to it. Native transfers are scanned after their own cursor,
+// so a scan failure does not hold back the watched events.
 //
 // Returns:
 // - error: an error if any issue occurs during event polling.
 func (h *EventHandler) pollEvents() error {
-	currentBlock, err := h.client.BlockNumber(h.ctx)
-	if err != nil {
-		return errors.Wrap(err, "failed to get current block number")
-	}
-
-	h.lastBlockMutex.RLock()
-	fromBlock := h.lastProcessedBlock
-	h.lastBlockMutex.RUnlock()
-
-	if fromBlock == 0 {
-		h.lastBlockMutex.Lock()
-		h.lastProcessedBlock = currentBlock
-		h.lastBlockMutex.Unlock()
-		return nil
+	if _, err := h.checkReorg(); err != nil {
+		return errors.Wrap(err, "failed to check for reorganization")
 	}
 
-	if currentBlock <= fromBlock {
-		return nil
+	currentBlock, err := h.getClient().BlockNumber(h.getContext())
+	if err != nil {
+		return errors.Wrap(err, "failed to get current block number")
 	}
 
-	toBlock := fromBlock + maxBlockRange
-	if toBlock > currentBlock {
-		toBlock = currentBlock
-	}
+	backfillErr := h.backfill(currentBlock)
 
-	if err := h.processBlockRange(fromBlock+1, toBlock); err != nil {
-		return errors.Wrap(err, "failed to process block range")
+	if err := h.scanNativeTransfers(maxBlockRange); err != nil {
+		h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to scan native transfers")
 	}
 
-	h.lastBlockMutex.Lock()
-	h.lastProcessedBlock = toBlock
-	h.lastBlockMutex.Unlock()
-
-	return nil
+	return backfillErr
 }
 
-// processBlockRange processes a block range and filters for FundsForwarded, FundsForwardedWithData, and Transfer events.
-// It queries logs for the specified block range and processes the events.
+// processBlockRange processes a block range and filters for the watched events.
+// It queries logs for the specified block range with the watch list queries and processes the events.
 //
 // Parameters:
 // - fromBlock: the starting block number.
@@ -100,44 +93,32 @@ func (h *EventHandler) pollEvents() error {
 // Returns:
 // - error: an error if any issue occurs during block range processing.
 func (h *EventHandler) processBlockRange(fromBlock, toBlock uint64) error {
-	relayQuery := ethereum.FilterQuery{
-		FromBlock: new(big.Int).SetUint64(fromBlock),
-		ToBlock:   new(big.Int).SetUint64(toBlock),
-		Addresses: []common.Address{common.HexToAddress(h.chainConfig.RelayReceiver)},
-		Topics: [][]common.Hash{{
-			crypto.Keccak256Hash([]byte("FundsForwarded()")),
-			crypto.Keccak256Hash([]byte("FundsForwardedWithData(bytes)")),
-		}},
-	}
-
-	transferQuery := ethereum.FilterQuery{
-		FromBlock: new(big.Int).SetUint64(fromBlock),
-		ToBlock:   new(big.Int).SetUint64(toBlock),
-		Topics: [][]common.Hash{
-			{crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))},
-			nil,
-			{common.BytesToHash(common.HexToAddress(h.solverAddress).Bytes())},
-		},
-	}
-
 	// Get logs for both queries concurrently using goroutines.
 	type queryResult struct {
 		logs []ethtypes.Log
 		err  error
 	}
 
-	relayResultChan := make(chan queryResult, 1)
-	transferResultChan := make(chan queryResult, 1)
+	filterLogs := func(query ethereum.FilterQuery, ok bool) chan queryResult {
+		resultChan := make(chan queryResult, 1)
+		if !ok {
+			resultChan <- queryResult{}
+			return resultChan
+		}
 
-	go func() {
-		logs, err := h.client.FilterLogs(h.ctx, relayQuery)
-		relayResultChan <- queryResult{logs: logs, err: err}
-	}()
+		query.FromBlock = new(big.Int).SetUint64(fromBlock)
+		query.ToBlock = new(big.Int).SetUint64(toBlock)
 
-	go func() {
-		logs, err := h.client.FilterLogs(h.ctx, transferQuery)
-		transferResultChan <- queryResult{logs: logs, err: err}
-	}()
+		go func() {
+			logs, err := h.getClient().FilterLogs(h.getContext(), query)
+			resultChan <- queryResult{logs: logs, err: err}
+		}()
+
+		return resultChan
+	}
+
+	relayResultChan := filterLogs(h.watchList.RelayQuery())
+	transferResultChan := filterLogs(h.watchList.TransferQuery(h.solverAddress))
 
 	// Wait for both queries to complete and handle any errors.
 	relayResult := <-relayResultChan
@@ -150,28 +131,14 @@ func (h *EventHandler) processBlockRange(fromBlock, toBlock uint64) error {
 		return errors.Wrap(transferResult.err, "failed to get transfer logs")
 	}
 
-	// Process all logs.
-	for _, log := range relayResult.logs {
-		if err := h.processEvent(utils.GetEventType(log), log); err != nil {
-			h.logger.WithFields(logrus.Fields{
-				"chain":     h.chainConfig.Name,
-				"eventType": "relay",
-				"txHash":    log.TxHash.Hex(),
-				"block":     log.BlockNumber,
-			}).WithError(err).Error("Failed to process relay log")
+	// Process all logs in chain order.
+	logs := append(relayResult.logs, transferResult.logs...)
+	sort.Slice(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
 		}
-	}
+		return logs[i].Index < logs[j].Index
+	})
 
-	for _, log := range transferResult.logs {
-		if err := h.processEvent(utils.GetEventType(log), log); err != nil {
-			h.logger.WithFields(logrus.Fields{
-				"chain":     h.chainConfig.Name,
-				"eventType": "transfer",
-				"txHash":    log.TxHash.Hex(),
-				"block":     log.BlockNumber,
-			}).WithError(err).Error("Failed to process transfer log")
-		}
-	}
-
-	return nil
+	return h.processLogs(logs)
 }
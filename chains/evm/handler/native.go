@@ -0,0 +1,603 @@
+package handler
+
+import (
+	"encoding/hex"
+	"github.com/ClipFinance/relay-lib/chains/evm/utils"
+	relaytypes "github.com/ClipFinance/relay-lib/common/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Block trace methods used to find internal native transfers.
+const (
+	tracerDebug       = "debug_traceBlockByNumber" // Geth call tracer.
+	tracerTrace       = "trace_block"              // OpenEthereum, Erigon and Nethermind traces.
+	tracerUnsupported = "none"                     // No trace method, top-level transactions only.
+)
+
+const (
+	// maxLiveNativeScanBlocks is the maximum number of blocks scanned for native transfers per tick while subscribed.
+	maxLiveNativeScanBlocks = uint64(100)
+	// nativeScanBatchSize is the number of blocks fetched and traced with one batch request.
+	nativeScanBatchSize = uint64(20)
+)
+
+// methodNotFoundCode is the JSON-RPC error code of an unsupported method.
+const methodNotFoundCode = -32601
+
+// rpcBlock is a block with its transactions, decoded from the JSON-RPC response field by field
+// so that transaction types unknown to go-ethereum, like OP Stack deposits, do not fail the block.
+type rpcBlock struct {
+	Number       hexutil.Uint64   `json:"number"`
+	Hash         common.Hash      `json:"hash"`
+	Timestamp    hexutil.Uint64   `json:"timestamp"`
+	Transactions []rpcTransaction `json:"transactions"`
+}
+
+// rpcTransaction is a transaction of a block, with the fields used to report a native transfer.
+type rpcTransaction struct {
+	Hash  common.Hash     `json:"hash"`
+	From  common.Address  `json:"from"`
+	To    *common.Address `json:"to"`
+	Value *hexutil.Big    `json:"value"`
+	Input hexutil.Bytes   `json:"input"`
+	Nonce hexutil.Uint64  `json:"nonce"`
+}
+
+// rpcReceipt is a transaction receipt, with the fields used to check that a transaction succeeded.
+type rpcReceipt struct {
+	TransactionHash common.Hash    `json:"transactionHash"`
+	Status          hexutil.Uint64 `json:"status"`
+}
+
+// nativeTransfer is a native value transfer to the solver found in a block.
+type nativeTransfer struct {
+	tx           *rpcTransaction // Transaction performing the transfer.
+	from         common.Address  // Account sending the value.
+	value        *big.Int        // Transferred value.
+	input        []byte          // Input data of the call.
+	traceAddress string          // Position of the call within the transaction, empty for the transaction itself.
+}
+
+// callFrame is a call reported by the Geth call tracer.
+type callFrame struct {
+	Type  string          `json:"type"`
+	From  common.Address  `json:"from"`
+	To    *common.Address `json:"to"`
+	Value *hexutil.Big    `json:"value"`
+	Input hexutil.Bytes   `json:"input"`
+	Error string          `json:"error"`
+	Calls []callFrame     `json:"calls"`
+	Logs  []callLog       `json:"logs"`
+}
+
+// callLog is a log reported by the Geth call tracer with logs enabled.
+type callLog struct {
+	Address  common.Address `json:"address"`
+	Topics   []common.Hash  `json:"topics"`
+	Data     hexutil.Bytes  `json:"data"`
+	Position hexutil.Uint   `json:"position"` // Number of subcalls of the frame made before the log.
+}
+
+// txTrace is the call tracer result of a transaction.
+type txTrace struct {
+	Result callFrame `json:"result"`
+}
+
+// blockTrace is a call reported by trace_block.
+type blockTrace struct {
+	Action struct {
+		CallType string          `json:"callType"`
+		From     common.Address  `json:"from"`
+		To       *common.Address `json:"to"`
+		Value    *hexutil.Big    `json:"value"`
+		Input    hexutil.Bytes   `json:"input"`
+	} `json:"action"`
+	Error               string `json:"error"`
+	TraceAddress        []int  `json:"traceAddress"`
+	TransactionPosition *int   `json:"transactionPosition"`
+	Type                string `json:"type"`
+}
+
+// scanNativeTransfers emits the native transfers to the solver made in the blocks after the native scan cursor,
+// at most maxBlocks of them, if ChainConfig.DetectNativeTransfers is set. The cursor is kept apart from the event
+// checkpoint, so a block that cannot be scanned holds back native transfer detection only.
+//
+// Parameters:
+// - maxBlocks: the maximum number of blocks to scan.
+//
+// Returns:
+// - error: an error if the block number or the cursor cannot be retrieved, or a block cannot be scanned.
+func (h *EventHandler) scanNativeTransfers(maxBlocks uint64) error {
+	if !h.chainConfig.DetectNativeTransfers {
+		return nil
+	}
+
+	head, err := h.getClient().BlockNumber(h.getContext())
+	if err != nil {
+		return errors.Wrap(err, "failed to get block number")
+	}
+
+	scanned, err := h.getNativeScannedBlock()
+	if err != nil {
+		return err
+	}
+
+	// Without a cursor, detection starts at the event checkpoint, or at the head without one either.
+	if scanned == 0 {
+		if scanned, err = h.getLastProcessedBlock(); err != nil {
+			return err
+		}
+		if scanned == 0 {
+			return h.saveNativeScannedBlock(head)
+		}
+	}
+
+	fromBlock := scanned + 1
+	if fromBlock > head {
+		return nil
+	}
+
+	toBlock := head
+	if toBlock-fromBlock >= maxBlocks {
+		toBlock = fromBlock + maxBlocks - 1
+	}
+
+	solver := common.HexToAddress(h.solverAddress)
+
+	for start := fromBlock; start <= toBlock; start += nativeScanBatchSize {
+		if err := h.getContext().Err(); err != nil {
+			return errors.Wrap(err, "native transfer scan cancelled")
+		}
+
+		end := start + nativeScanBatchSize - 1
+		if end > toBlock {
+			end = toBlock
+		}
+
+		blocks, err := h.getBlocks(start, end)
+		if err != nil {
+			return err
+		}
+
+		transfers, scanErr := h.tracedNativeTransfers(blocks, solver)
+		for i, blockTransfers := range transfers {
+			h.emitNativeTransfers(blocks[i], blockTransfers, solver)
+
+			if err := h.saveNativeScannedBlock(uint64(blocks[i].Number)); err != nil {
+				return err
+			}
+		}
+
+		if scanErr != nil {
+			return errors.Wrapf(scanErr, "failed to scan block %d for native transfers", uint64(blocks[len(transfers)].Number))
+		}
+	}
+
+	return nil
+}
+
+// getBlocks fetches a block range with its transactions in one batch request.
+//
+// Parameters:
+// - fromBlock: the first block to fetch.
+// - toBlock: the last block to fetch.
+//
+// Returns:
+// - []*rpcBlock: the blocks in order.
+// - error: an error if a block cannot be fetched.
+func (h *EventHandler) getBlocks(fromBlock, toBlock uint64) ([]*rpcBlock, error) {
+	blocks := make([]*rpcBlock, toBlock-fromBlock+1)
+	calls := make([]rpc.BatchElem, len(blocks))
+	for i := range blocks {
+		calls[i] = rpc.BatchElem{
+			Method: "eth_getBlockByNumber",
+			Args:   []interface{}{hexutil.EncodeUint64(fromBlock + uint64(i)), true},
+			Result: &blocks[i],
+		}
+	}
+
+	if err := h.batchCall(calls); err != nil {
+		return nil, err
+	}
+
+	for i, call := range calls {
+		if call.Error != nil {
+			return nil, errors.Wrapf(call.Error, "failed to get block %d", fromBlock+uint64(i))
+		}
+		if blocks[i] == nil {
+			return nil, errors.Errorf("block %d not found", fromBlock+uint64(i))
+		}
+	}
+
+	return blocks, nil
+}
+
+// emitNativeTransfers emits the native transfers to the solver made in a block.
+// Transfers forwarded by watched contracts are skipped, their own events report them.
+//
+// Parameters:
+// - block: the block.
+// - transfers: the native transfers to the solver made in the block.
+// - solver: the solver address.
+func (h *EventHandler) emitNativeTransfers(block *rpcBlock, transfers []nativeTransfer, solver common.Address) {
+	for _, transfer := range transfers {
+		if h.watchList.WatchesContract(transfer.from) {
+			continue
+		}
+
+		if len(transfer.input) == 0 {
+			h.logger.WithFields(logrus.Fields{
+				"chain":  h.chainConfig.Name,
+				"txHash": transfer.tx.Hash.Hex(),
+				"value":  transfer.value.String(),
+			}).Warn("Skipping native transfer to solver without quote ID")
+			continue
+		}
+
+		h.emitEvent(relaytypes.ChainEvent{
+			ChainID:           h.chainConfig.ChainID,
+			BlockNumber:       uint64(block.Number),
+			BlockHash:         block.Hash.String(),
+			FromTokenAddr:     utils.ZeroAddress,
+			FromAddress:       transfer.from.Hex(),
+			ToAddress:         solver.Hex(),
+			TransactionHash:   transfer.tx.Hash.String(),
+			QuoteID:           "0x" + hex.EncodeToString(transfer.input),
+			FromTxMinedAt:     time.Unix(int64(block.Timestamp), 0),
+			TransactionAmount: transfer.value.String(),
+			FromNonce:         uint64(transfer.tx.Nonce),
+			Metadata: utils.EvmMetadata{
+				EventType:    utils.EventTypeNativeTransfer,
+				TraceAddress: transfer.traceAddress,
+			},
+		})
+	}
+}
+
+// tracedNativeTransfers returns the successful native transfers to the solver made in blocks, including
+// internal transfers if the node supports a block trace method. Without one, only transactions sent
+// directly to the solver are found. The trace method is detected on the first block.
+//
+// Parameters:
+// - blocks: the blocks.
+// - solver: the solver address.
+//
+// Returns:
+// - [][]nativeTransfer: the transfers of each block in execution order, for the blocks before the first
+// block that cannot be scanned.
+// - error: an error if the traces or receipts of a block cannot be retrieved.
+func (h *EventHandler) tracedNativeTransfers(blocks []*rpcBlock, solver common.Address) ([][]nativeTransfer, error) {
+	h.lastBlockMutex.RLock()
+	tracer := h.tracer
+	h.lastBlockMutex.RUnlock()
+
+	if tracer == "" {
+		detected, err := h.detectTracer(blocks[0], solver)
+		if err != nil {
+			return nil, err
+		}
+		tracer = detected
+	}
+
+	switch tracer {
+	case tracerDebug:
+		return h.debugTraceTransfers(blocks, solver)
+	case tracerTrace:
+		return h.traceBlockTransfers(blocks, solver)
+	default:
+		var transfers [][]nativeTransfer
+		for _, block := range blocks {
+			blockTransfers, err := h.topLevelTransfers(block, solver)
+			if err != nil {
+				return transfers, err
+			}
+			transfers = append(transfers, blockTransfers)
+		}
+		return transfers, nil
+	}
+}
+
+// detectTracer finds the block trace method supported by the node by tracing a block with each of them.
+//
+// Parameters:
+// - block: the block to trace.
+// - solver: the solver address.
+//
+// Returns:
+// - string: the supported trace method, tracerUnsupported if there is none.
+// - error: an error if tracing fails for another reason than an unsupported method.
+func (h *EventHandler) detectTracer(block *rpcBlock, solver common.Address) (string, error) {
+	for _, method := range []string{tracerDebug, tracerTrace} {
+		var err error
+		if method == tracerDebug {
+			_, err = h.debugTraceTransfers([]*rpcBlock{block}, solver)
+		} else {
+			_, err = h.traceBlockTransfers([]*rpcBlock{block}, solver)
+		}
+
+		if err == nil {
+			h.setTracer(method)
+			return method, nil
+		}
+		if !isMethodUnsupported(errors.Cause(err)) {
+			return "", errors.Wrapf(err, "failed to trace block with %s", method)
+		}
+	}
+
+	h.logger.WithField("chain", h.chainConfig.Name).Warn("Node does not support block traces, detecting top-level native transfers only")
+	h.setTracer(tracerUnsupported)
+
+	return tracerUnsupported, nil
+}
+
+// setTracer records the block trace method supported by the node.
+func (h *EventHandler) setTracer(method string) {
+	h.lastBlockMutex.Lock()
+	defer h.lastBlockMutex.Unlock()
+
+	h.tracer = method
+}
+
+// traceBlocks calls a block trace method for each block in one batch request.
+//
+// Parameters:
+// - method: the block trace method.
+// - blocks: the blocks.
+// - results: the results, one per block.
+// - extra: the arguments following the block number.
+//
+// Returns:
+// - int: the number of blocks traced before the first failed trace.
+// - error: the error of the first failed trace, or of the batch request.
+func (h *EventHandler) traceBlocks(method string, blocks []*rpcBlock, results []interface{}, extra ...interface{}) (int, error) {
+	calls := make([]rpc.BatchElem, len(blocks))
+	for i, block := range blocks {
+		calls[i] = rpc.BatchElem{
+			Method: method,
+			Args:   append([]interface{}{hexutil.EncodeUint64(uint64(block.Number))}, extra...),
+			Result: results[i],
+		}
+	}
+
+	if err := h.batchCall(calls); err != nil {
+		return 0, err
+	}
+
+	for i, call := range calls {
+		if call.Error != nil {
+			return i, call.Error
+		}
+	}
+
+	return len(calls), nil
+}
+
+// debugTraceTransfers finds the native transfers to the solver with the Geth call tracer.
+func (h *EventHandler) debugTraceTransfers(blocks []*rpcBlock, solver common.Address) ([][]nativeTransfer, error) {
+	traces := make([][]txTrace, len(blocks))
+	results := make([]interface{}, len(blocks))
+	for i := range traces {
+		results[i] = &traces[i]
+	}
+
+	traced, err := h.traceBlocks(tracerDebug, blocks, results, map[string]string{"tracer": "callTracer"})
+
+	var transfers [][]nativeTransfer
+	for i, block := range blocks[:traced] {
+		txs := block.Transactions
+		if len(traces[i]) != len(txs) {
+			return transfers, errors.Errorf("got %d traces for %d transactions", len(traces[i]), len(txs))
+		}
+
+		var blockTransfers []nativeTransfer
+		for j, trace := range traces[i] {
+			blockTransfers = appendFrameTransfers(blockTransfers, &txs[j], trace.Result, "", solver)
+		}
+		transfers = append(transfers, blockTransfers)
+	}
+
+	return transfers, err
+}
+
+// appendFrameTransfers appends the native transfers to the solver made by a call and its successful subcalls.
+func appendFrameTransfers(transfers []nativeTransfer, tx *rpcTransaction, frame callFrame, traceAddress string, solver common.Address) []nativeTransfer {
+	// A failed call reverts its subcalls too.
+	if frame.Error != "" {
+		return transfers
+	}
+
+	if frame.Type == "CALL" && frame.To != nil && *frame.To == solver && frame.Value != nil && frame.Value.ToInt().Sign() > 0 {
+		transfers = append(transfers, nativeTransfer{
+			tx:           tx,
+			from:         frame.From,
+			value:        frame.Value.ToInt(),
+			input:        frame.Input,
+			traceAddress: traceAddress,
+		})
+	}
+
+	for i, call := range frame.Calls {
+		transfers = appendFrameTransfers(transfers, tx, call, joinTraceAddress(traceAddress, i), solver)
+	}
+
+	return transfers
+}
+
+// traceBlockTransfers finds the native transfers to the solver with trace_block.
+func (h *EventHandler) traceBlockTransfers(blocks []*rpcBlock, solver common.Address) ([][]nativeTransfer, error) {
+	traces := make([][]blockTrace, len(blocks))
+	results := make([]interface{}, len(blocks))
+	for i := range traces {
+		results[i] = &traces[i]
+	}
+
+	traced, err := h.traceBlocks(tracerTrace, blocks, results)
+
+	var transfers [][]nativeTransfer
+	for i, block := range blocks[:traced] {
+		transfers = append(transfers, blockTraceTransfers(block, traces[i], solver))
+	}
+
+	return transfers, err
+}
+
+// blockTraceTransfers returns the native transfers to the solver among the trace_block traces of a block.
+func blockTraceTransfers(block *rpcBlock, traces []blockTrace, solver common.Address) []nativeTransfer {
+	txs := block.Transactions
+	failed := make(map[int][]string)
+
+	var transfers []nativeTransfer
+	for _, trace := range traces {
+		// Block rewards have no transaction.
+		if trace.TransactionPosition == nil || *trace.TransactionPosition >= len(txs) {
+			continue
+		}
+		position := *trace.TransactionPosition
+
+		traceAddress := ""
+		for _, index := range trace.TraceAddress {
+			traceAddress = joinTraceAddress(traceAddress, index)
+		}
+
+		// Traces are ordered depth first, a failed call is listed before the subcalls it reverts.
+		if trace.Error != "" {
+			failed[position] = append(failed[position], traceAddress)
+			continue
+		}
+		if isReverted(failed[position], traceAddress) {
+			continue
+		}
+
+		action := trace.Action
+		if trace.Type != "call" || action.CallType != "call" || action.To == nil || *action.To != solver ||
+			action.Value == nil || action.Value.ToInt().Sign() <= 0 {
+			continue
+		}
+
+		transfers = append(transfers, nativeTransfer{
+			tx:           &txs[position],
+			from:         action.From,
+			value:        action.Value.ToInt(),
+			input:        action.Input,
+			traceAddress: traceAddress,
+		})
+	}
+
+	return transfers
+}
+
+// topLevelTransfers finds the successful transactions sending native value directly to the solver.
+// The receipts are fetched with eth_getBlockReceipts, or with one batch request where it is not supported.
+func (h *EventHandler) topLevelTransfers(block *rpcBlock, solver common.Address) ([]nativeTransfer, error) {
+	var candidates []*rpcTransaction
+	for i := range block.Transactions {
+		tx := &block.Transactions[i]
+		if tx.To != nil && *tx.To == solver && tx.Value != nil && tx.Value.ToInt().Sign() > 0 {
+			candidates = append(candidates, tx)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	statuses, err := h.receiptStatuses(block, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	var transfers []nativeTransfer
+	for _, tx := range candidates {
+		status, ok := statuses[tx.Hash]
+		if !ok {
+			return nil, errors.Errorf("receipt of transaction %s not found", tx.Hash.Hex())
+		}
+		if status != ethtypes.ReceiptStatusSuccessful {
+			continue
+		}
+
+		transfers = append(transfers, nativeTransfer{
+			tx:    tx,
+			from:  tx.From,
+			value: tx.Value.ToInt(),
+			input: tx.Input,
+		})
+	}
+
+	return transfers, nil
+}
+
+// receiptStatuses returns the receipt status of transactions of a block by transaction hash.
+func (h *EventHandler) receiptStatuses(block *rpcBlock, txs []*rpcTransaction) (map[common.Hash]uint64, error) {
+	statuses := make(map[common.Hash]uint64)
+
+	var receipts []rpcReceipt
+	err := h.getClient().Client().CallContext(h.getContext(), &receipts, "eth_getBlockReceipts", hexutil.EncodeUint64(uint64(block.Number)))
+	if err == nil {
+		for _, receipt := range receipts {
+			statuses[receipt.TransactionHash] = uint64(receipt.Status)
+		}
+		return statuses, nil
+	}
+	if !isMethodUnsupported(err) {
+		return nil, errors.Wrap(err, "failed to get block receipts")
+	}
+
+	receipts = make([]rpcReceipt, len(txs))
+	calls := make([]rpc.BatchElem, len(txs))
+	for i, tx := range txs {
+		calls[i] = rpc.BatchElem{Method: "eth_getTransactionReceipt", Args: []interface{}{tx.Hash}, Result: &receipts[i]}
+	}
+
+	if err := h.batchCall(calls); err != nil {
+		return nil, err
+	}
+
+	for i, call := range calls {
+		if call.Error != nil {
+			return nil, errors.Wrap(call.Error, "failed to get transaction receipt")
+		}
+		statuses[txs[i].Hash] = uint64(receipts[i].Status)
+	}
+
+	return statuses, nil
+}
+
+// joinTraceAddress returns the trace address of the index-th subcall of a call.
+func joinTraceAddress(parent string, index int) string {
+	if parent == "" {
+		return strconv.Itoa(index)
+	}
+	return parent + "." + strconv.Itoa(index)
+}
+
+// isReverted reports whether a call is a subcall of one of the failed calls.
+func isReverted(failed []string, traceAddress string) bool {
+	for _, parent := range failed {
+		if parent == "" || strings.HasPrefix(traceAddress, parent+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// isMethodUnsupported reports whether an RPC error means the node does not provide the method.
+func isMethodUnsupported(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == methodNotFoundCode {
+		return true
+	}
+
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "not supported") || strings.Contains(message, "not available") ||
+		strings.Contains(message, "does not exist")
+}
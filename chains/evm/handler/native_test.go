@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	relaytypes "github.com/ClipFinance/relay-lib/common/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/sirupsen/logrus"
+	"io"
+	"testing"
+)
+
+var (
+	testSolver    = common.HexToAddress("0x5000000000000000000000000000000000000005")
+	testDepositor = common.HexToAddress("0x3000000000000000000000000000000000000003")
+	testWallet    = common.HexToAddress("0x4000000000000000000000000000000000000004")
+)
+
+// standInEth answers eth_getBlockByNumber with blocks holding an OP Stack deposit
+// transaction, which go-ethereum cannot decode, and a transfer to the solver through a smart wallet.
+type standInEth struct{}
+
+// GetBlockByNumber returns the block with its transactions.
+func (standInEth) GetBlockByNumber(number hexutil.Uint64, full bool) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(`{
+		"number": "%s",
+		"hash": "%s",
+		"timestamp": "0x6553f100",
+		"transactions": [
+			{"type": "0x7e", "hash": "0x%064x", "from": "0xdeaddeaddeaddeaddeaddeaddeaddeaddead0001",
+			 "to": "0x4200000000000000000000000000000000000015", "value": "0x0", "input": "0x", "nonce": "0x0",
+			 "sourceHash": "0x%064x", "mint": "0x0", "isSystemTx": false},
+			{"type": "0x2", "hash": "0x%064x", "from": "%s", "to": "%s", "value": "0x0", "input": "0x", "nonce": "0x7"}
+		]
+	}`, number, common.BigToHash(common.Big1).Hex(), 100+uint64(number), 200, 300+uint64(number), testDepositor.Hex(), testWallet.Hex()))
+}
+
+// standInDebug answers debug_traceBlockByNumber with the call tracer.
+type standInDebug struct{}
+
+// TraceBlockByNumber returns a trace per transaction of the block, the wallet forwarding value to the solver.
+func (standInDebug) TraceBlockByNumber(number hexutil.Uint64, config map[string]string) []txTrace {
+	value := (*hexutil.Big)(common.Big2)
+	return []txTrace{
+		{Result: callFrame{Type: "CALL", From: testDepositor, To: &testWallet, Value: new(hexutil.Big)}},
+		{Result: callFrame{
+			Type:  "CALL",
+			From:  testDepositor,
+			To:    &testWallet,
+			Value: new(hexutil.Big),
+			Calls: []callFrame{{Type: "CALL", From: testWallet, To: &testSolver, Value: value, Input: []byte{0xab}}},
+		}},
+	}
+}
+
+// newStandInHandler creates an event handler connected to an in-process node with the given services.
+func newStandInHandler(t *testing.T, services map[string]interface{}) *EventHandler {
+	t.Helper()
+
+	server := rpc.NewServer()
+	for name, service := range services {
+		if err := server.RegisterName(name, service); err != nil {
+			t.Fatalf("RegisterName(%s) error = %v", name, err)
+		}
+	}
+	t.Cleanup(server.Stop)
+
+	client := ethclient.NewClient(rpc.DialInProc(server))
+	t.Cleanup(client.Close)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	return &EventHandler{
+		ctx:         context.Background(),
+		chainConfig: &relaytypes.ChainConfig{Name: "test"},
+		logger:      logger,
+		client:      client,
+	}
+}
+
+func TestTracedNativeTransfers(t *testing.T) {
+	tests := []struct {
+		name       string
+		services   map[string]interface{}
+		wantTracer string
+		wantFrom   common.Address
+	}{
+		{
+			name:       "call tracer",
+			services:   map[string]interface{}{"eth": standInEth{}, "debug": standInDebug{}},
+			wantTracer: tracerDebug,
+			wantFrom:   testWallet,
+		},
+		{
+			name:       "no trace method",
+			services:   map[string]interface{}{"eth": standInEth{}},
+			wantTracer: tracerUnsupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newStandInHandler(t, tt.services)
+
+			blocks, err := h.getBlocks(10, 12)
+			if err != nil {
+				t.Fatalf("getBlocks() error = %v", err)
+			}
+			if len(blocks) != 3 || uint64(blocks[2].Number) != 12 || len(blocks[2].Transactions) != 2 {
+				t.Fatalf("getBlocks() = %+v, want blocks 10 to 12 with 2 transactions", blocks)
+			}
+
+			transfers, err := h.tracedNativeTransfers(blocks, testSolver)
+			if err != nil {
+				t.Fatalf("tracedNativeTransfers() error = %v", err)
+			}
+			if h.tracer != tt.wantTracer {
+				t.Fatalf("tracer = %q, want %q", h.tracer, tt.wantTracer)
+			}
+			if len(transfers) != len(blocks) {
+				t.Fatalf("got transfers of %d blocks, want %d", len(transfers), len(blocks))
+			}
+
+			for i, blockTransfers := range transfers {
+				if tt.wantFrom == (common.Address{}) {
+					if len(blockTransfers) != 0 {
+						t.Fatalf("block %d transfers = %+v, want none", i, blockTransfers)
+					}
+					continue
+				}
+				if len(blockTransfers) != 1 || blockTransfers[0].from != tt.wantFrom || blockTransfers[0].traceAddress != "0" ||
+					blockTransfers[0].tx != &blocks[i].Transactions[1] {
+					t.Fatalf("block %d transfers = %+v, want the wallet transfer", i, blockTransfers)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,423 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ClipFinance/relay-lib/chains/evm/utils"
+	relaytypes "github.com/ClipFinance/relay-lib/common/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultEventQueueSize is the number of events queued in memory if ChainConfig.EventQueueSize is not set.
+	defaultEventQueueSize = 1000
+	// spillBatchSize is the maximum number of spilled events loaded back into memory at once.
+	spillBatchSize = 100
+)
+
+// eventQueue buffers the events between the listener and the application.
+type eventQueue struct {
+	mutex       sync.Mutex                    // Mutex for the queued events, the spilling flag and the counters.
+	spillMutex  sync.Mutex                    // Mutex serializing the spill store operations with the spilling flag.
+	spillCursor int64                         // Position of the last spilled event loaded into memory.
+	events      []queuedEvent                 // Events queued in memory, oldest first.
+	delivering  *queuedEvent                  // Event being handed over to the event channel.
+	capacity    int                           // Maximum number of events queued in memory.
+	policy      relaytypes.EventQueueOverflow // Policy applied when the queue is full.
+	spilling    bool                          // Whether events wait in the spill store, new events are spilled behind them.
+	delivered   uint64                        // Number of delivered events.
+	spilled     uint64                        // Number of spilled events.
+	dropped     uint64                        // Number of dropped events.
+	ready       chan struct{}                 // Signalled when an event is queued or spilled.
+	space       chan struct{}                 // Signalled when an event is taken from memory.
+	deliverer   sync.Once                     // Guard for starting the delivery loop.
+}
+
+// queuedEvent is an event queued in memory.
+type queuedEvent struct {
+	event   relaytypes.ChainEvent // Event to deliver.
+	spillID int64                 // Position of the event in the spill store, 0 if it was not spilled.
+}
+
+// spilledEvent is the encoding of an event in the spill store, keeping the type of its metadata.
+type spilledEvent struct {
+	Event    relaytypes.ChainEvent `json:"event"`
+	Metadata utils.EvmMetadata     `json:"metadata"`
+}
+
+// newEventQueue creates an empty event queue for the configured size and overflow policy.
+//
+// Parameters:
+// - config: the chain configuration.
+//
+// Returns:
+// - *eventQueue: a new eventQueue instance.
+// - error: an error if the overflow policy is unknown or misses its spill store.
+func newEventQueue(config *relaytypes.ChainConfig) (*eventQueue, error) {
+	capacity := config.EventQueueSize
+	if capacity <= 0 {
+		capacity = defaultEventQueueSize
+	}
+
+	policy := config.EventQueueOverflow
+	switch policy {
+	case "":
+		policy = relaytypes.EventQueueBlock
+	case relaytypes.EventQueueBlock, relaytypes.EventQueueDropOldest:
+	case relaytypes.EventQueueSpill:
+		if config.EventSpillStore == nil {
+			return nil, errors.New("event queue spill policy requires an event spill store")
+		}
+	default:
+		return nil, errors.Errorf("unknown event queue overflow policy %q", policy)
+	}
+
+	return &eventQueue{
+		capacity: capacity,
+		policy:   policy,
+		// Events spilled before a restart are delivered first.
+		spilling: policy == relaytypes.EventQueueSpill,
+		ready:    make(chan struct{}, 1),
+		space:    make(chan struct{}, 1),
+	}, nil
+}
+
+// statsLocked returns a snapshot of the queue. The caller must hold the queue mutex.
+func (q *eventQueue) statsLocked() relaytypes.EventQueueStats {
+	return relaytypes.EventQueueStats{
+		Depth:     len(q.events),
+		Capacity:  q.capacity,
+		Spilling:  q.spilling,
+		Delivered: q.delivered,
+		Spilled:   q.spilled,
+		Dropped:   q.dropped,
+	}
+}
+
+// signal wakes up a waiter of the channel without blocking.
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// QueueStats returns a snapshot of the event queue.
+//
+// Returns:
+// - relaytypes.EventQueueStats: the queue state.
+func (h *EventHandler) QueueStats() relaytypes.EventQueueStats {
+	h.queue.mutex.Lock()
+	defer h.queue.mutex.Unlock()
+
+	return h.queue.statsLocked()
+}
+
+// enqueueEvent hands an event over to the delivery loop. When the queue is full the overflow policy applies:
+// the listener waits for queue space, spills the event to ChainConfig.EventSpillStore, or drops the oldest event.
+// A failed spill falls back to waiting for queue space.
+//
+// Parameters:
+// - event: the event to deliver.
+func (h *EventHandler) enqueueEvent(event relaytypes.ChainEvent) {
+	q := h.queue
+	spill := q.policy == relaytypes.EventQueueSpill
+
+	for {
+		q.mutex.Lock()
+		full := len(q.events) >= q.capacity
+		spillNext := spill && (q.spilling || full)
+		q.mutex.Unlock()
+
+		if spillNext {
+			err := h.spillEvent(event)
+			if err == nil {
+				return
+			}
+			h.logger.WithFields(logrus.Fields{
+				"chain":  h.chainConfig.Name,
+				"txHash": event.TransactionHash,
+			}).WithError(err).Error("Failed to spill event, waiting for event queue space")
+			spill = false
+		}
+
+		q.mutex.Lock()
+		if len(q.events) < q.capacity {
+			q.events = append(q.events, queuedEvent{event: event})
+			stats := q.statsLocked()
+			q.mutex.Unlock()
+
+			signal(q.ready)
+			h.observeQueue(stats)
+			return
+		}
+
+		if q.policy == relaytypes.EventQueueDropOldest {
+			dropped := q.events[0].event
+			q.events = append(q.events[1:], queuedEvent{event: event})
+			q.dropped++
+			stats := q.statsLocked()
+			q.mutex.Unlock()
+
+			h.logger.WithFields(logrus.Fields{
+				"chain":    h.chainConfig.Name,
+				"txHash":   dropped.TransactionHash,
+				"quoteId":  dropped.QuoteID,
+				"capacity": q.capacity,
+			}).Error("Event queue full, dropped oldest event")
+
+			// The dropped event is emitted again if its block is processed again.
+			h.forgetProcessed(dropped)
+
+			if metrics := h.chainConfig.EventQueueMetrics; metrics != nil {
+				metrics.EventDropped(h.chainConfig.ChainID, dropped)
+			}
+			signal(q.ready)
+			h.observeQueue(stats)
+			return
+		}
+		q.mutex.Unlock()
+
+		select {
+		case <-q.space:
+		case <-h.stopChan:
+			return
+		}
+	}
+}
+
+// spillEvent appends an event to the spill store and marks the queue as spilling.
+//
+// Parameters:
+// - event: the event to spill.
+//
+// Returns:
+// - error: an error if the event cannot be encoded or stored.
+func (h *EventHandler) spillEvent(event relaytypes.ChainEvent) error {
+	q := h.queue
+
+	record := spilledEvent{Event: event}
+	if metadata, ok := event.Metadata.(utils.EvmMetadata); ok {
+		record.Metadata = metadata
+	}
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode event")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	q.spillMutex.Lock()
+	defer q.spillMutex.Unlock()
+
+	if err := h.chainConfig.EventSpillStore.SpillEvent(ctx, h.chainConfig.ChainID, payload); err != nil {
+		return errors.Wrap(err, "failed to store event")
+	}
+
+	q.mutex.Lock()
+	q.spilling = true
+	q.spilled++
+	stats := q.statsLocked()
+	q.mutex.Unlock()
+
+	signal(q.ready)
+	h.observeQueue(stats)
+	return nil
+}
+
+// loadSpilledEvents copies the oldest spilled events not loaded yet into memory, and clears the spilling flag
+// once all spilled events are loaded. The events stay in the spill store until they are delivered.
+//
+// Returns:
+// - error: an error if the events cannot be loaded.
+func (h *EventHandler) loadSpilledEvents() error {
+	q := h.queue
+
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	q.spillMutex.Lock()
+	defer q.spillMutex.Unlock()
+
+	records, err := h.chainConfig.EventSpillStore.PeekSpilledEvents(ctx, h.chainConfig.ChainID, q.spillCursor, spillBatchSize)
+	if err != nil {
+		return errors.Wrap(err, "failed to get spilled events")
+	}
+
+	events := make([]queuedEvent, 0, len(records))
+	for _, spilled := range records {
+		q.spillCursor = spilled.ID
+
+		var record spilledEvent
+		if err := json.Unmarshal(spilled.Payload, &record); err != nil {
+			h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to decode spilled event")
+			continue
+		}
+		record.Event.Metadata = record.Metadata
+		events = append(events, queuedEvent{event: record.Event, spillID: spilled.ID})
+	}
+
+	q.mutex.Lock()
+	q.events = append(events, q.events...)
+	if len(records) == 0 {
+		q.spilling = false
+	}
+	q.mutex.Unlock()
+
+	return nil
+}
+
+// deleteSpilledEvents removes the delivered spilled events from the spill store. A failure is logged,
+// the events are delivered again after a restart.
+//
+// Parameters:
+// - throughID: the position of the last delivered spilled event.
+func (h *EventHandler) deleteSpilledEvents(throughID int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	if err := h.chainConfig.EventSpillStore.DeleteSpilledEvents(ctx, h.chainConfig.ChainID, throughID); err != nil {
+		h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to delete delivered spilled events")
+	}
+}
+
+// startDelivery starts delivering the queued events to the event channel once.
+// The loop runs until Stop and keeps its queue across client updates.
+func (h *EventHandler) startDelivery() {
+	h.queue.deliverer.Do(func() {
+		go h.deliverEvents()
+	})
+}
+
+// deliverEvents sends the queued events to the event channel in order, loading spilled events
+// back into memory when the memory queue runs empty.
+func (h *EventHandler) deliverEvents() {
+	q := h.queue
+
+	for {
+		q.mutex.Lock()
+		var queued queuedEvent
+		hasEvent := len(q.events) > 0
+		if hasEvent {
+			queued = q.events[0]
+			q.events = q.events[1:]
+			q.delivering = &queued
+		}
+		spilling := q.spilling
+		q.mutex.Unlock()
+
+		if !hasEvent {
+			if spilling {
+				if err := h.loadSpilledEvents(); err != nil {
+					h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to load spilled events")
+					select {
+					case <-time.After(reconnectTimeout):
+					case <-h.stopChan:
+						return
+					}
+				}
+				continue
+			}
+
+			select {
+			case <-q.ready:
+			case <-h.stopChan:
+				return
+			}
+			continue
+		}
+
+		signal(q.space)
+
+		// Retractions are not recorded, the retracted event was forgotten.
+		if !queued.event.Removed && !h.markProcessed(queued.event) {
+			h.logger.WithFields(logrus.Fields{
+				"chain":  h.chainConfig.Name,
+				"txHash": queued.event.TransactionHash,
+				"block":  queued.event.BlockNumber,
+			}).Debug("Skipping event delivered before")
+			h.finishDelivery(queued)
+			continue
+		}
+
+		select {
+		case h.eventChan <- queued.event:
+		case <-h.stopChan:
+			if !queued.event.Removed {
+				h.forgetProcessed(queued.event)
+			}
+			return
+		}
+
+		h.finishDelivery(queued)
+
+		q.mutex.Lock()
+		q.delivered++
+		stats := q.statsLocked()
+		q.mutex.Unlock()
+
+		h.observeQueue(stats)
+	}
+}
+
+// finishDelivery removes a delivered or skipped event from the spill store if it was spilled.
+//
+// Parameters:
+// - queued: the event.
+func (h *EventHandler) finishDelivery(queued queuedEvent) {
+	q := h.queue
+
+	q.mutex.Lock()
+	q.delivering = nil
+	q.mutex.Unlock()
+
+	if queued.spillID != 0 {
+		h.deleteSpilledEvents(queued.spillID)
+	}
+}
+
+// oldestBlock returns the lowest block of the events queued in memory or being delivered. Events loaded from
+// the spill store are left out, the spill store keeps them until they are delivered.
+//
+// Returns:
+// - uint64: the block number.
+// - bool: false if no such event is queued.
+func (q *eventQueue) oldestBlock() (uint64, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var oldest uint64
+	found := false
+	consider := func(queued queuedEvent) {
+		if queued.spillID != 0 || queued.event.Removed {
+			return
+		}
+		if !found || queued.event.BlockNumber < oldest {
+			oldest = queued.event.BlockNumber
+			found = true
+		}
+	}
+
+	for _, queued := range q.events {
+		consider(queued)
+	}
+	if q.delivering != nil {
+		consider(*q.delivering)
+	}
+
+	return oldest, found
+}
+
+// observeQueue reports the queue state to ChainConfig.EventQueueMetrics if set.
+//
+// Parameters:
+// - stats: the queue state.
+func (h *EventHandler) observeQueue(stats relaytypes.EventQueueStats) {
+	if metrics := h.chainConfig.EventQueueMetrics; metrics != nil {
+		metrics.ObserveQueue(h.chainConfig.ChainID, stats)
+	}
+}
@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	relaytypes "github.com/ClipFinance/relay-lib/common/types"
+	"github.com/sirupsen/logrus"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memorySpillStore is an in-memory EventSpillStore.
+type memorySpillStore struct {
+	mutex  sync.Mutex
+	events []relaytypes.SpilledEvent
+	nextID int64
+}
+
+func (s *memorySpillStore) SpillEvent(ctx context.Context, chainID uint64, payload []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.nextID++
+	s.events = append(s.events, relaytypes.SpilledEvent{ID: s.nextID, Payload: payload})
+	return nil
+}
+
+func (s *memorySpillStore) PeekSpilledEvents(ctx context.Context, chainID uint64, afterID int64, limit int) ([]relaytypes.SpilledEvent, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var events []relaytypes.SpilledEvent
+	for _, event := range s.events {
+		if event.ID > afterID && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (s *memorySpillStore) DeleteSpilledEvents(ctx context.Context, chainID uint64, throughID int64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	kept := s.events[:0]
+	for _, event := range s.events {
+		if event.ID > throughID {
+			kept = append(kept, event)
+		}
+	}
+	s.events = kept
+	return nil
+}
+
+// ids returns the positions of the stored events.
+func (s *memorySpillStore) ids() []int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	ids := make([]int64, 0, len(s.events))
+	for _, event := range s.events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func TestSpilledEventsKeptUntilDelivered(t *testing.T) {
+	store := &memorySpillStore{}
+	for _, hash := range []string{"0x01", "0x02", "0x03"} {
+		payload, err := json.Marshal(spilledEvent{Event: relaytypes.ChainEvent{TransactionHash: hash}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.SpillEvent(context.Background(), 1, payload); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := &relaytypes.ChainConfig{
+		ChainID:            1,
+		Name:               "test",
+		EventQueueOverflow: relaytypes.EventQueueSpill,
+		EventSpillStore:    store,
+	}
+	queue, err := newEventQueue(config)
+	if err != nil {
+		t.Fatalf("newEventQueue() error = %v", err)
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	h := &EventHandler{
+		chainConfig: config,
+		logger:      logger,
+		eventChan:   make(chan relaytypes.ChainEvent),
+		queue:       queue,
+		dedup:       newDedupCache(dedupTTL, maxDedupEntries),
+		stopChan:    make(chan struct{}),
+	}
+
+	done := make(chan struct{})
+	go func() {
+		h.deliverEvents()
+		close(done)
+	}()
+
+	if event := <-h.eventChan; event.TransactionHash != "0x01" {
+		t.Fatalf("delivered %s, want 0x01", event.TransactionHash)
+	}
+
+	// The delivered event is removed, the events loaded into memory stay until they are delivered.
+	deadline := time.Now().Add(time.Second)
+	for len(store.ids()) != 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	close(h.stopChan)
+	<-done
+
+	if ids := store.ids(); len(ids) != 2 || ids[0] != 2 || ids[1] != 3 {
+		t.Fatalf("spill store holds %v, want [2 3]", ids)
+	}
+}
+
+// memoryDedupStore is an in-memory EventDedupStore.
+type memoryDedupStore struct {
+	mutex sync.Mutex
+	keys  map[string]bool
+}
+
+func (s *memoryDedupStore) MarkEventProcessed(ctx context.Context, chainID uint64, key string, ttl time.Duration) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.keys[key] {
+		return false, nil
+	}
+	s.keys[key] = true
+	return true, nil
+}
+
+func (s *memoryDedupStore) ForgetEvent(ctx context.Context, chainID uint64, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.keys, key)
+	return nil
+}
+
+// has reports whether the key is recorded.
+func (s *memoryDedupStore) has(key string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.keys[key]
+}
+
+func TestEventsMarkedProcessedOnDelivery(t *testing.T) {
+	newHandler := func(policy relaytypes.EventQueueOverflow, capacity int) (*EventHandler, *memoryDedupStore) {
+		store := &memoryDedupStore{keys: make(map[string]bool)}
+		config := &relaytypes.ChainConfig{
+			ChainID:            1,
+			Name:               "test",
+			EventQueueSize:     capacity,
+			EventQueueOverflow: policy,
+			EventDedupStore:    store,
+		}
+		queue, err := newEventQueue(config)
+		if err != nil {
+			t.Fatalf("newEventQueue() error = %v", err)
+		}
+
+		logger := logrus.New()
+		logger.SetOutput(io.Discard)
+
+		return &EventHandler{
+			chainConfig: config,
+			logger:      logger,
+			eventChan:   make(chan relaytypes.ChainEvent),
+			queue:       queue,
+			dedup:       newDedupCache(dedupTTL, maxDedupEntries),
+			blocks:      newBlockWindow(),
+			pending:     newPendingEvents(),
+			stopChan:    make(chan struct{}),
+		}, store
+	}
+
+	first := relaytypes.ChainEvent{TransactionHash: "0x01", BlockNumber: 10}
+	second := relaytypes.ChainEvent{TransactionHash: "0x02", BlockNumber: 11}
+
+	t.Run("delivered and undelivered at stop", func(t *testing.T) {
+		h, store := newHandler(relaytypes.EventQueueBlock, 10)
+		h.sendEvent(first)
+
+		done := make(chan struct{})
+		go func() {
+			h.deliverEvents()
+			close(done)
+		}()
+
+		<-h.eventChan
+		h.sendEvent(second)
+
+		// The checkpoint stays behind the event waiting for delivery.
+		deadline := time.Now().Add(time.Second)
+		for !store.has(eventKey(second)) && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if block, ok := h.undeliveredBlock(); !ok || block != second.BlockNumber {
+			t.Fatalf("undeliveredBlock() = %d %v, want %d", block, ok, second.BlockNumber)
+		}
+
+		close(h.stopChan)
+		<-done
+
+		if !store.has(eventKey(first)) {
+			t.Fatal("delivered event not recorded")
+		}
+		if store.has(eventKey(second)) {
+			t.Fatal("event undelivered at stop still recorded")
+		}
+	})
+
+	t.Run("dropped", func(t *testing.T) {
+		h, _ := newHandler(relaytypes.EventQueueDropOldest, 1)
+		h.sendEvent(first)
+		h.sendEvent(second)
+
+		if h.dedup.contains(eventKey(first)) {
+			t.Fatal("dropped event still remembered")
+		}
+		if block, ok := h.undeliveredBlock(); !ok || block != second.BlockNumber {
+			t.Fatalf("undeliveredBlock() = %d %v, want %d", block, ok, second.BlockNumber)
+		}
+	})
+}
@@ -0,0 +1,282 @@
+package handler
+
+import (
+	"github.com/ClipFinance/relay-lib/chains/evm/utils"
+	relaytypes "github.com/ClipFinance/relay-lib/common/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"math/big"
+	"sort"
+	"sync"
+)
+
+// reorgWindow is the number of recent blocks whose hashes and events are kept to detect reorganizations.
+const reorgWindow = 128
+
+// blockWindow keeps the hashes of recently processed blocks and the events emitted from them.
+type blockWindow struct {
+	mutex  sync.Mutex                         // Mutex for the window.
+	hashes map[uint64]common.Hash             // Recorded block hashes by block number.
+	events map[uint64][]relaytypes.ChainEvent // Emitted events by block number.
+}
+
+// newBlockWindow creates an empty block window.
+//
+// Returns:
+// - *blockWindow: a new blockWindow instance.
+func newBlockWindow() *blockWindow {
+	return &blockWindow{
+		hashes: make(map[uint64]common.Hash),
+		events: make(map[uint64][]relaytypes.ChainEvent),
+	}
+}
+
+// recordBlock records the hash of a processed block and drops blocks that left the window.
+//
+// Parameters:
+// - number: the block number.
+// - hash: the block hash.
+func (w *blockWindow) recordBlock(number uint64, hash common.Hash) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.hashes[number] = hash
+
+	if number < reorgWindow {
+		return
+	}
+	for recorded := range w.hashes {
+		if recorded <= number-reorgWindow {
+			delete(w.hashes, recorded)
+			delete(w.events, recorded)
+		}
+	}
+}
+
+// recordEvent records an emitted event so it can be retracted if its block is orphaned.
+//
+// Parameters:
+// - event: the emitted event.
+func (w *blockWindow) recordEvent(event relaytypes.ChainEvent) {
+	w.recordBlock(event.BlockNumber, common.HexToHash(event.BlockHash))
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.events[event.BlockNumber] = append(w.events[event.BlockNumber], event)
+}
+
+// removeEvent removes the recorded event of a log.
+//
+// Parameters:
+// - log: the log of the event.
+//
+// Returns:
+// - relaytypes.ChainEvent: the removed event.
+// - bool: false if no event was recorded for the log.
+func (w *blockWindow) removeEvent(log ethtypes.Log) (relaytypes.ChainEvent, bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	events := w.events[log.BlockNumber]
+	for i, event := range events {
+		metadata, ok := event.Metadata.(utils.EvmMetadata)
+		if !ok || event.TransactionHash != log.TxHash.String() || event.BlockHash != log.BlockHash.String() || metadata.LogIndex != log.Index {
+			continue
+		}
+
+		w.events[log.BlockNumber] = append(events[:i:i], events[i+1:]...)
+		return event, true
+	}
+
+	return relaytypes.ChainEvent{}, false
+}
+
+// blocks returns the recorded block numbers, newest first, with their hashes.
+//
+// Returns:
+// - []uint64: the recorded block numbers.
+// - map[uint64]common.Hash: the recorded hashes by block number.
+func (w *blockWindow) blocks() ([]uint64, map[uint64]common.Hash) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	numbers := make([]uint64, 0, len(w.hashes))
+	hashes := make(map[uint64]common.Hash, len(w.hashes))
+	for number, hash := range w.hashes {
+		numbers = append(numbers, number)
+		hashes[number] = hash
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] > numbers[j] })
+
+	return numbers, hashes
+}
+
+// truncate drops the blocks after the fork point and returns their events that are not part of the canonical chain.
+//
+// Parameters:
+// - forkPoint: the last block shared with the canonical chain.
+// - canonical: the canonical block hashes of the dropped blocks, where known.
+//
+// Returns:
+// - []relaytypes.ChainEvent: the orphaned events, oldest first.
+func (w *blockWindow) truncate(forkPoint uint64, canonical map[uint64]common.Hash) []relaytypes.ChainEvent {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	var orphaned []relaytypes.ChainEvent
+	for number, events := range w.events {
+		if number <= forkPoint {
+			continue
+		}
+		for _, event := range events {
+			if hash, ok := canonical[number]; !ok || common.HexToHash(event.BlockHash) != hash {
+				orphaned = append(orphaned, event)
+			}
+		}
+		delete(w.events, number)
+	}
+
+	for number := range w.hashes {
+		if number > forkPoint {
+			delete(w.hashes, number)
+		}
+	}
+
+	sort.SliceStable(orphaned, func(i, j int) bool { return orphaned[i].BlockNumber < orphaned[j].BlockNumber })
+
+	return orphaned
+}
+
+// recordBlockHash records the canonical hash of a processed block.
+//
+// Parameters:
+// - number: the block number.
+func (h *EventHandler) recordBlockHash(number uint64) {
+	header, err := h.getClient().HeaderByNumber(h.getContext(), new(big.Int).SetUint64(number))
+	if err != nil {
+		h.logger.WithFields(logrus.Fields{
+			"chain": h.chainConfig.Name,
+			"block": number,
+		}).WithError(err).Warn("Failed to get block hash for reorg detection")
+		return
+	}
+
+	h.blocks.recordBlock(number, header.Hash())
+}
+
+// checkReorg compares the recorded block hashes with the canonical chain. On a mismatch it retracts the events
+// of the orphaned blocks and rewinds the checkpoint to the last common block, so the blocks are processed again
+// and their events re-emitted from the canonical chain.
+//
+// Returns:
+// - bool: true if a reorganization was detected.
+// - error: an error if the canonical headers or the checkpoint cannot be accessed.
+func (h *EventHandler) checkReorg() (bool, error) {
+	numbers, hashes := h.blocks.blocks()
+	if len(numbers) == 0 {
+		return false, nil
+	}
+
+	// Blocks are hash-linked, so the newest recorded block matching means no recorded block was orphaned.
+	canonical := make(map[uint64]common.Hash)
+	var forkPoint uint64
+	if oldest := numbers[len(numbers)-1]; oldest > 0 {
+		forkPoint = oldest - 1
+	}
+	forkFound := false
+	for _, number := range numbers {
+		header, err := h.getClient().HeaderByNumber(h.getContext(), new(big.Int).SetUint64(number))
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return false, errors.Wrap(err, "failed to get canonical header")
+		}
+
+		if header != nil {
+			if header.Hash() == hashes[number] {
+				forkPoint = number
+				forkFound = true
+				break
+			}
+			canonical[number] = header.Hash()
+		}
+	}
+
+	if forkFound && forkPoint == numbers[0] {
+		return false, nil
+	}
+
+	if !forkFound {
+		h.logger.WithFields(logrus.Fields{
+			"chain":  h.chainConfig.Name,
+			"window": reorgWindow,
+		}).Error("Chain reorganization deeper than the tracked window")
+	}
+
+	h.backfillMutex.Lock()
+	defer h.backfillMutex.Unlock()
+
+	orphaned := h.blocks.truncate(forkPoint, canonical)
+
+	h.logger.WithFields(logrus.Fields{
+		"chain":          h.chainConfig.Name,
+		"forkPoint":      forkPoint,
+		"orphanedEvents": len(orphaned),
+	}).Warn("Chain reorganization detected")
+
+	for _, event := range orphaned {
+		h.retractEvent(event)
+	}
+
+	return true, h.rewindCheckpoint(forkPoint)
+}
+
+// retractLog retracts the event of a log removed from the canonical chain. An event still waiting for
+// confirmation is dropped silently, without a recorded event a retraction carrying the log identifiers is sent.
+//
+// Parameters:
+// - log: the removed log.
+func (h *EventHandler) retractLog(log ethtypes.Log) {
+	if h.pending.remove(logKey(log)) {
+		return
+	}
+
+	event, ok := h.blocks.removeEvent(log)
+	if !ok {
+		event = relaytypes.ChainEvent{
+			ChainID:         h.chainConfig.ChainID,
+			BlockNumber:     log.BlockNumber,
+			BlockHash:       log.BlockHash.String(),
+			FromTokenAddr:   log.Address.String(),
+			TransactionHash: log.TxHash.String(),
+			Metadata: utils.EvmMetadata{
+				EventType: h.watchList.EventType(log),
+				LogIndex:  log.Index,
+				Data:      log.Data,
+			},
+		}
+	}
+
+	h.retractEvent(event)
+}
+
+// retractEvent sends a copy of an emitted event marked as removed.
+//
+// Parameters:
+// - event: the emitted event.
+func (h *EventHandler) retractEvent(event relaytypes.ChainEvent) {
+	h.forgetProcessed(event)
+	event.Removed = true
+
+	h.logger.WithFields(logrus.Fields{
+		"chain":     h.chainConfig.Name,
+		"txHash":    event.TransactionHash,
+		"blockHash": event.BlockHash,
+		"block":     event.BlockNumber,
+		"quoteId":   event.QuoteID,
+	}).Warn("Retracting event from orphaned block")
+
+	h.enqueueEvent(event)
+}
@@ -2,11 +2,8 @@ package handler
 
 import (
 	"context"
-	"github.com/ClipFinance/relay-lib/chains/evm/utils"
 	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -41,6 +38,14 @@ func (s *Subscription) Close() {
 	}
 }
 
+// Err returns the error channel of the subscription, or nil if it is not established.
+func (s *Subscription) Err() <-chan error {
+	if s.Subscription == nil {
+		return nil
+	}
+	return s.Subscription.Err()
+}
+
 // StartWSSubscription starts the WebSocket subscription for relay and transfer events.
 // It sets up the necessary subscriptions and starts handling events in a separate goroutine.
 //
@@ -52,18 +57,21 @@ func (h *EventHandler) StartWSSubscription() error {
 	}
 
 	go h.handleEvents()
+	h.startConfirmationLoop()
+	h.startDelivery()
 
 	return nil
 }
 
 // reconnectSubscription attempts to reconnect the specified subscription type (relay or transfer).
-// It retries the connection up to a maximum number of attempts, with a delay between attempts.
+// It retries the connection up to a maximum number of attempts, with a delay between attempts,
+// and falls back to HTTP polling once the attempts are exhausted.
 //
 // Parameters:
 // - subscriptionType: the type of subscription to reconnect ("relay" or "transfer").
 //
 // Returns:
-// - error: an error if the reconnection fails or the context is cancelled.
+// - error: errPollingFallback if the handler fell back to HTTP polling, or an error if the context is cancelled.
 func (h *EventHandler) reconnectSubscription(subscriptionType string) error {
 	// Close existing subscriptions
 	if h.relaySubscription != nil {
@@ -73,79 +81,156 @@ func (h *EventHandler) reconnectSubscription(subscriptionType string) error {
 		h.transferSubscription.Close()
 	}
 
-	ticker := time.NewTicker(retryTimeout)
-	defer ticker.Stop()
-
-	for {
-		for attempt := 1; attempt <= maxReconnectAttempts; attempt++ {
-			select {
-			case <-h.ctx.Done():
-				return errors.New("context cancelled during reconnection")
-			default:
-				h.logger.WithFields(logrus.Fields{
-					"chain":   h.chainConfig.Name,
-					"type":    subscriptionType,
-					"attempt": attempt,
-				}).Info("Attempting to reconnect subscription")
-
-				if err := h.setupSubscriptions(h.solverAddress); err != nil {
-					h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to reconnect subscription")
-
-					if attempt == maxReconnectAttempts {
-						h.logger.WithFields(logrus.Fields{
-							"chain": h.chainConfig.Name,
-							"type":  subscriptionType,
-						}).Warn("Max reconnect attempts reached, waiting for retry timeout")
-						<-ticker.C
-						attempt = 0
-						continue
-					}
-
+	for attempt := 1; attempt <= maxReconnectAttempts; attempt++ {
+		select {
+		case <-h.getContext().Done():
+			return errors.New("context cancelled during reconnection")
+		default:
+			h.logger.WithFields(logrus.Fields{
+				"chain":   h.chainConfig.Name,
+				"type":    subscriptionType,
+				"attempt": attempt,
+			}).Info("Attempting to reconnect subscription")
+
+			if err := h.setupSubscriptions(h.solverAddress); err != nil {
+				h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to reconnect subscription")
+
+				if attempt < maxReconnectAttempts {
 					time.Sleep(reconnectTimeout)
-					continue
 				}
-
-				h.logger.WithFields(logrus.Fields{
-					"chain": h.chainConfig.Name,
-					"type":  subscriptionType,
-				}).Info("Successfully reconnected subscription")
-				return nil
+				continue
 			}
+
+			h.logger.WithFields(logrus.Fields{
+				"chain": h.chainConfig.Name,
+				"type":  subscriptionType,
+			}).Info("Successfully reconnected subscription")
+			return nil
 		}
 	}
+
+	h.logger.WithFields(logrus.Fields{
+		"chain": h.chainConfig.Name,
+		"type":  subscriptionType,
+	}).Warn("Max reconnect attempts reached")
+
+	h.fallbackToPolling()
+	return errPollingFallback
 }
 
 // handleEvents handles incoming events from the relay and transfer subscriptions.
 // It processes events and attempts to reconnect subscriptions in case of errors.
 func (h *EventHandler) handleEvents() {
+	checkpointTicker := time.NewTicker(checkpointInterval)
+	defer checkpointTicker.Stop()
+
+	// Logs of the head seen at the previous tick were delivered at least one interval ago.
+	var previousHead uint64
+
+	// Native transfers have no logs to subscribe to, new blocks are scanned for them instead.
+	var nativeTick <-chan time.Time
+	if h.chainConfig.DetectNativeTransfers {
+		nativeTicker := time.NewTicker(defaultPollingInterval)
+		defer nativeTicker.Stop()
+		nativeTick = nativeTicker.C
+	}
+
 	for {
+		// The polling fallback owns the handler until the subscriptions are recovered.
+		if h.isFallback() {
+			return
+		}
+
 		select {
-		case <-h.ctx.Done():
+		case <-h.getContext().Done():
 			return
 
-		case err := <-h.relaySubscription.Subscription.Err():
+		case <-checkpointTicker.C:
+			reorged, err := h.checkReorg()
+			if err != nil {
+				h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to check for reorganization")
+			}
+
+			if previousHead > 0 && !reorged {
+				h.advanceLiveCheckpoint(previousHead)
+				h.recordBlockHash(previousHead)
+			}
+
+			head, err := h.getClient().BlockNumber(h.getContext())
+			if err != nil {
+				h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Warn("Failed to get block number for checkpoint")
+				continue
+			}
+			previousHead = head
+
+			if reorged {
+				h.startBackfill(head)
+			}
+
+		case <-nativeTick:
+			if err := h.scanNativeTransfers(maxLiveNativeScanBlocks); err != nil {
+				h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to scan native transfers")
+			}
+
+		case err := <-h.relaySubscription.Err():
 			h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Relay subscription error")
+			if h.isFallback() {
+				return
+			}
 			if err := h.reconnectSubscription("relay"); err != nil {
+				if errors.Is(err, errPollingFallback) {
+					return
+				}
 				h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to reconnect relay subscription")
 			}
 
-		case err := <-h.transferSubscription.Subscription.Err():
+		case err := <-h.transferSubscription.Err():
 			h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Transfer subscription error")
+			if h.isFallback() {
+				return
+			}
 			if err := h.reconnectSubscription("transfer"); err != nil {
+				if errors.Is(err, errPollingFallback) {
+					return
+				}
 				h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to reconnect transfer subscription")
 			}
 
 		case event := <-h.relaySubscription.EventChan:
-			err := h.processEvent(utils.GetEventType(event), event)
-			if err != nil {
+			if event.Removed {
+				h.retractLog(event)
+				continue
+			}
+			if !h.isLiveEvent(event) {
+				continue
+			}
+			if err := h.processEvent(event); err != nil {
 				h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to process relay event")
+				// The checkpoint stays below the event until the backfill processes its block again.
+				h.holdLiveCheckpoint(event.BlockNumber)
+				h.startBackfill(event.BlockNumber)
+				continue
 			}
+			// Later logs of the same block may still arrive.
+			h.advanceLiveCheckpoint(event.BlockNumber - 1)
 
 		case event := <-h.transferSubscription.EventChan:
-			err := h.processEvent(utils.GetEventType(event), event)
-			if err != nil {
+			if event.Removed {
+				h.retractLog(event)
+				continue
+			}
+			if !h.isLiveEvent(event) {
+				continue
+			}
+			if err := h.processEvent(event); err != nil {
 				h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to process transfer event")
+				// The checkpoint stays below the event until the backfill processes its block again.
+				h.holdLiveCheckpoint(event.BlockNumber)
+				h.startBackfill(event.BlockNumber)
+				continue
 			}
+			// Later logs of the same block may still arrive.
+			h.advanceLiveCheckpoint(event.BlockNumber - 1)
 		}
 	}
 }
@@ -161,28 +246,15 @@ func (h *EventHandler) setupSubscriptions(solverAddress string) error {
 	if h.relaySubscription.Subscription != nil {
 		h.relaySubscription.Subscription.Unsubscribe()
 	}
-	if h.relaySubscription.Subscription != nil {
+	if h.transferSubscription.Subscription != nil {
 		h.transferSubscription.Subscription.Unsubscribe()
 	}
 
-	ctx, cancel := context.WithTimeout(h.ctx, contextTimeout)
+	ctx, cancel := context.WithTimeout(h.getContext(), contextTimeout)
 	defer cancel()
 
-	relayQuery := ethereum.FilterQuery{
-		Addresses: []common.Address{common.HexToAddress(h.chainConfig.RelayReceiver)},
-		Topics: [][]common.Hash{{
-			crypto.Keccak256Hash([]byte("FundsForwarded()")),
-			crypto.Keccak256Hash([]byte("FundsForwardedWithData(bytes)")),
-		}},
-	}
-
-	transferQuery := ethereum.FilterQuery{
-		Topics: [][]common.Hash{
-			{crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))},
-			nil,
-			{common.BytesToHash(common.HexToAddress(solverAddress).Bytes())},
-		},
-	}
+	relayQuery, watchRelay := h.watchList.RelayQuery()
+	transferQuery, watchTransfer := h.watchList.TransferQuery(solverAddress)
 
 	var err error
 	for attempt := 0; attempt < maxReconnectAttempts; attempt++ {
@@ -190,12 +262,17 @@ func (h *EventHandler) setupSubscriptions(solverAddress string) error {
 			return errors.New("context cancelled while setting up subscriptions")
 		}
 
-		if err = h.setupRelayReceiverSubscription(ctx, relayQuery); err != nil {
-			h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to setup relay subscription")
-			time.Sleep(reconnectTimeout)
-			continue
+		if watchRelay {
+			if err = h.setupRelayReceiverSubscription(ctx, relayQuery); err != nil {
+				h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to setup relay subscription")
+				time.Sleep(reconnectTimeout)
+				continue
+			}
 		}
 
+		if !watchTransfer {
+			break
+		}
 		if err = h.setupTransferSubscription(ctx, transferQuery); err != nil {
 			h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to setup transfer subscription")
 			time.Sleep(reconnectTimeout)
@@ -212,9 +289,58 @@ func (h *EventHandler) setupSubscriptions(solverAddress string) error {
 		return err
 	}
 
+	// Events up to the current block are recovered from the checkpoint, the subscriptions deliver the rest.
+	blockNumber, err := h.getClient().BlockNumber(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get block number")
+	}
+
+	h.lastBlockMutex.Lock()
+	h.liveFromBlock = blockNumber + 1
+	h.lastBlockMutex.Unlock()
+
+	h.startBackfill(blockNumber)
+
 	return nil
 }
 
+// startBackfill backfills the events up to a block in the background, retrying until it succeeds
+// or the handler is stopped.
+//
+// Parameters:
+// - toBlock: the last block to process.
+func (h *EventHandler) startBackfill(toBlock uint64) {
+	go func(ctx context.Context) {
+		for {
+			err := h.backfill(toBlock)
+			if err == nil {
+				return
+			}
+			h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to backfill events")
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(reconnectTimeout):
+			}
+		}
+	}(h.getContext())
+}
+
+// isLiveEvent reports whether a subscription event is past the backfilled blocks.
+//
+// Parameters:
+// - event: the event log.
+//
+// Returns:
+// - bool: false if the backfill covers the block of the event.
+func (h *EventHandler) isLiveEvent(event ethtypes.Log) bool {
+	h.lastBlockMutex.RLock()
+	defer h.lastBlockMutex.RUnlock()
+
+	return event.BlockNumber >= h.liveFromBlock
+}
+
 // setupRelayReceiverSubscription sets up the relay receiver subscription using the provided filter query.
 //
 // Parameters:
@@ -232,7 +358,7 @@ func (h *EventHandler) setupRelayReceiverSubscription(ctx context.Context, query
 		h.relaySubscription.Subscription.Unsubscribe()
 	}
 
-	blockNumber, err := h.client.BlockNumber(ctx)
+	blockNumber, err := h.getClient().BlockNumber(ctx)
 	if err != nil {
 		return errors.Wrap(err, "failed to get block number")
 	}
@@ -240,7 +366,7 @@ func (h *EventHandler) setupRelayReceiverSubscription(ctx context.Context, query
 	query.FromBlock = new(big.Int).SetUint64(blockNumber)
 
 	eventChan := make(chan ethtypes.Log)
-	sub, err := h.client.SubscribeFilterLogs(ctx, query, eventChan)
+	sub, err := h.getClient().SubscribeFilterLogs(ctx, query, eventChan)
 	if err != nil {
 		return errors.Wrap(err, "failed to subscribe to relay events")
 	}
@@ -273,7 +399,7 @@ func (h *EventHandler) setupTransferSubscription(ctx context.Context, query ethe
 		h.transferSubscription.Subscription.Unsubscribe()
 	}
 
-	blockNumber, err := h.client.BlockNumber(ctx)
+	blockNumber, err := h.getClient().BlockNumber(ctx)
 	if err != nil {
 		return errors.Wrap(err, "failed to get block number")
 	}
@@ -281,7 +407,7 @@ func (h *EventHandler) setupTransferSubscription(ctx context.Context, query ethe
 	query.FromBlock = new(big.Int).SetUint64(blockNumber)
 
 	eventChan := make(chan ethtypes.Log)
-	sub, err := h.client.SubscribeFilterLogs(ctx, query, eventChan)
+	sub, err := h.getClient().SubscribeFilterLogs(ctx, query, eventChan)
 	if err != nil {
 		return errors.Wrap(err, "failed to subscribe to transfer events")
 	}
@@ -0,0 +1,328 @@
+package evm
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"math/big"
+	"sync"
+	"time"
+)
+
+const (
+	// nonceGapGracePeriod is the time after which a sent transaction unknown to the node is treated as dropped.
+	nonceGapGracePeriod = 2 * time.Minute
+	// selfTransferGas is the gas limit of the self-transfer filling a nonce gap.
+	selfTransferGas = 21000
+)
+
+// nonceClient is the subset of the Ethereum client used by the nonce manager.
+type nonceClient interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+}
+
+// nonceManager hands out the nonces of a single account to concurrent senders.
+// Nonces are reserved locally and reconciled with the node's pending and latest nonce on every reservation.
+type nonceManager struct {
+	address     common.Address       // Account the nonces belong to.
+	mutex       sync.Mutex           // Mutex for the nonce state.
+	initialized bool                 // Whether the next nonce was loaded from the node.
+	next        uint64               // Next nonce never handed out.
+	reserved    map[uint64]struct{}  // Nonces handed out and not yet sent or released.
+	released    map[uint64]struct{}  // Nonces below next that were released after a failed send.
+	sent        map[uint64]time.Time // Nonces accepted by the node and not yet mined.
+}
+
+// newNonceManager creates a new nonce manager for the given account.
+//
+// Parameters:
+// - address: the account the nonces belong to.
+//
+// Returns:
+// - *nonceManager: a new nonceManager instance.
+func newNonceManager(address common.Address) *nonceManager {
+	return &nonceManager{
+		address:  address,
+		reserved: make(map[uint64]struct{}),
+		released: make(map[uint64]struct{}),
+		sent:     make(map[uint64]time.Time),
+	}
+}
+
+// reserve reserves the next nonce for a transaction. Released nonces are handed out first, lowest first,
+// so a failed send does not leave a gap. The caller must call markSent or release with the nonce.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - client: the client used to reconcile with the node.
+//
+// Returns:
+// - uint64: the reserved nonce.
+// - error: an error if the node nonces cannot be loaded.
+func (m *nonceManager) reserve(ctx context.Context, client nonceClient) (uint64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if err := m.reconcile(ctx, client); err != nil {
+		return 0, err
+	}
+
+	if nonce, ok := m.lowestReleased(); ok {
+		delete(m.released, nonce)
+		m.reserved[nonce] = struct{}{}
+		return nonce, nil
+	}
+
+	nonce := m.next
+	m.next++
+	m.reserved[nonce] = struct{}{}
+
+	return nonce, nil
+}
+
+// reserveNonce reserves a specific released nonce, used to fill a gap.
+//
+// Parameters:
+// - nonce: the nonce to reserve.
+//
+// Returns:
+// - bool: false if the nonce is not released anymore.
+func (m *nonceManager) reserveNonce(nonce uint64) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.released[nonce]; !ok {
+		return false
+	}
+
+	delete(m.released, nonce)
+	m.reserved[nonce] = struct{}{}
+
+	return true
+}
+
+// markSent records that the transaction with the reserved nonce was accepted by the node.
+//
+// Parameters:
+// - nonce: the reserved nonce.
+func (m *nonceManager) markSent(nonce uint64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	delete(m.reserved, nonce)
+	m.sent[nonce] = time.Now()
+}
+
+// release returns a reserved nonce whose transaction was not sent.
+//
+// Parameters:
+// - nonce: the reserved nonce.
+//
+// Returns:
+// - bool: true if transactions with higher nonces were already sent, so the released nonce blocks them.
+func (m *nonceManager) release(nonce uint64) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	delete(m.reserved, nonce)
+	m.released[nonce] = struct{}{}
+
+	// Shrink the range instead of leaving trailing gaps.
+	for m.next > 0 {
+		if _, ok := m.released[m.next-1]; !ok {
+			break
+		}
+		delete(m.released, m.next-1)
+		m.next--
+	}
+
+	if _, ok := m.released[nonce]; !ok {
+		return false
+	}
+
+	for sentNonce := range m.sent {
+		if sentNonce > nonce {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reconcile aligns the local state with the node: mined and externally used nonces are dropped,
+// the next nonce catches up with the node's pending nonce, and a sent transaction the node no longer
+// knows about is released so its nonce is reused.
+func (m *nonceManager) reconcile(ctx context.Context, client nonceClient) error {
+	pending, err := client.PendingNonceAt(ctx, m.address)
+	if err != nil {
+		return errors.Wrap(err, "failed to get pending nonce")
+	}
+
+	latest, err := client.NonceAt(ctx, m.address, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to get latest nonce")
+	}
+
+	for nonce := range m.sent {
+		if nonce < latest {
+			delete(m.sent, nonce)
+		}
+	}
+
+	for nonce := range m.released {
+		if nonce < pending {
+			delete(m.released, nonce)
+		}
+	}
+
+	if !m.initialized || pending > m.next {
+		m.next = pending
+		m.initialized = true
+	}
+
+	// The node's pending nonce is the first nonce it has no transaction for.
+	if sentAt, ok := m.sent[pending]; ok && pending < m.next && time.Since(sentAt) > nonceGapGracePeriod {
+		delete(m.sent, pending)
+		m.released[pending] = struct{}{}
+	}
+
+	return nil
+}
+
+// inFlight returns the number of transactions reserved or sent and not yet mined, after reconciling with the node.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - client: the client used to reconcile with the node.
+//
+// Returns:
+// - int: the number of transactions in flight.
+// - error: an error if the node nonces cannot be loaded.
+func (m *nonceManager) inFlight(ctx context.Context, client nonceClient) (int, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if err := m.reconcile(ctx, client); err != nil {
+		return 0, err
+	}
+
+	return len(m.reserved) + len(m.sent), nil
+}
+
+// lowestReleased returns the lowest released nonce.
+func (m *nonceManager) lowestReleased() (uint64, bool) {
+	var lowest uint64
+	found := false
+	for nonce := range m.released {
+		if !found || nonce < lowest {
+			lowest = nonce
+			found = true
+		}
+	}
+	return lowest, found
+}
+
+// releaseNonce releases a nonce of a wallet after a failed send and, if it blocks already sent transactions,
+// fills it with a self-transfer in the background.
+//
+// Parameters:
+// - w: the wallet the nonce belongs to.
+// - nonce: the reserved nonce.
+func (e *evm) releaseNonce(w *wallet, nonce uint64) {
+	if !w.nonceManager.release(nonce) {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), waitTimeout)
+		defer cancel()
+
+		if err := e.fillNonceGap(ctx, w, nonce); err != nil {
+			e.logger.WithFields(logrus.Fields{
+				"chain":  e.config.Name,
+				"wallet": w.signer.Address().Hex(),
+				"nonce":  nonce,
+			}).WithError(err).Error("Failed to fill nonce gap")
+		}
+	}()
+}
+
+// fillNonceGap sends a zero value self-transfer with the given released nonce of a wallet
+// to unblock the transactions after it.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - w: the wallet the nonce belongs to.
+// - nonce: the released nonce.
+//
+// Returns:
+// - error: an error if the transaction cannot be built or sent, the nonce is released again in that case
+// unless the transaction may have been accepted.
+func (e *evm) fillNonceGap(ctx context.Context, w *wallet, nonce uint64) error {
+	if !w.nonceManager.reserveNonce(nonce) {
+		return nil
+	}
+
+	e.clientMutex.RLock()
+	client := e.client
+	e.clientMutex.RUnlock()
+
+	if client == nil {
+		w.nonceManager.release(nonce)
+		return errors.New("client not initialized")
+	}
+
+	toAddress := w.signer.Address()
+	chainID := new(big.Int).SetUint64(e.config.ChainID)
+
+	var tx *ethtypes.Transaction
+	if e.config.TxType == TxTypeEIP1559 {
+		gasPriceData, err := e.getEIP1559GasPrice(ctx)
+		if err != nil {
+			w.nonceManager.release(nonce)
+			return errors.Wrap(err, "failed to get EIP-1559 gas price")
+		}
+
+		tx = ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     nonce,
+			GasTipCap: gasPriceData.MaxPriorityFeePerGas,
+			GasFeeCap: gasPriceData.MaxFeePerGas,
+			Gas:       selfTransferGas,
+			To:        &toAddress,
+			Value:     big.NewInt(0),
+		})
+	} else {
+		gasPrice, err := client.SuggestGasPrice(ctx)
+		if err != nil {
+			w.nonceManager.release(nonce)
+			return errors.Wrap(err, "failed to get gas price")
+		}
+
+		tx = ethtypes.NewTransaction(nonce, toAddress, big.NewInt(0), selfTransferGas, gasPrice, nil)
+	}
+
+	signedTx, err := e.signAndSendTransaction(ctx, w.signer, tx)
+	if err != nil {
+		if errors.Is(err, errBroadcastUnknown) {
+			w.nonceManager.markSent(nonce)
+		} else {
+			w.nonceManager.release(nonce)
+		}
+		return err
+	}
+
+	w.nonceManager.markSent(nonce)
+
+	e.logger.WithFields(logrus.Fields{
+		"chain":  e.config.Name,
+		"wallet": toAddress.Hex(),
+		"nonce":  nonce,
+		"txHash": signedTx.Hash().Hex(),
+	}).Info("Filled nonce gap with self-transfer")
+
+	return nil
+}
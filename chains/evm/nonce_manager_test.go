@@ -0,0 +1,134 @@
+package evm
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// fakeNonceClient reports fixed pending and latest nonces.
+type fakeNonceClient struct {
+	pending uint64
+	latest  uint64
+}
+
+func (c *fakeNonceClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return c.pending, nil
+}
+
+func (c *fakeNonceClient) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return c.latest, nil
+}
+
+func TestNonceManager(t *testing.T) {
+	reserve := func(t *testing.T, m *nonceManager, client nonceClient, want uint64) {
+		t.Helper()
+
+		nonce, err := m.reserve(context.Background(), client)
+		if err != nil {
+			t.Fatalf("reserve() error = %v", err)
+		}
+		if nonce != want {
+			t.Fatalf("reserve() = %d, want %d", nonce, want)
+		}
+	}
+
+	tests := []struct {
+		name string
+		run  func(t *testing.T, m *nonceManager, client *fakeNonceClient)
+	}{
+		{
+			name: "released nonce handed out again",
+			run: func(t *testing.T, m *nonceManager, client *fakeNonceClient) {
+				reserve(t, m, client, 5)
+				reserve(t, m, client, 6)
+				reserve(t, m, client, 7)
+				m.markSent(6)
+				m.markSent(7)
+
+				m.release(5)
+				reserve(t, m, client, 5)
+				reserve(t, m, client, 8)
+			},
+		},
+		{
+			name: "trailing release shrinks next",
+			run: func(t *testing.T, m *nonceManager, client *fakeNonceClient) {
+				reserve(t, m, client, 5)
+				reserve(t, m, client, 6)
+				reserve(t, m, client, 7)
+
+				m.release(6)
+				m.release(7)
+				if m.next != 6 || len(m.released) != 0 {
+					t.Fatalf("next = %d released = %v, want 6 and none", m.next, m.released)
+				}
+				reserve(t, m, client, 6)
+			},
+		},
+		{
+			name: "release blocks only higher sent nonces",
+			run: func(t *testing.T, m *nonceManager, client *fakeNonceClient) {
+				reserve(t, m, client, 5)
+				reserve(t, m, client, 6)
+				m.markSent(5)
+
+				if m.release(6) {
+					t.Fatal("release() = true with only lower nonces sent")
+				}
+
+				reserve(t, m, client, 6)
+				reserve(t, m, client, 7)
+				m.markSent(7)
+
+				if !m.release(6) {
+					t.Fatal("release() = false with a higher nonce sent")
+				}
+			},
+		},
+		{
+			name: "dropped sent nonce released after grace period",
+			run: func(t *testing.T, m *nonceManager, client *fakeNonceClient) {
+				reserve(t, m, client, 5)
+				reserve(t, m, client, 6)
+				m.markSent(5)
+				m.markSent(6)
+
+				// The node lost the transaction with nonce 5 and stays at it.
+				inFlight, err := m.inFlight(context.Background(), client)
+				if err != nil {
+					t.Fatalf("inFlight() error = %v", err)
+				}
+				if inFlight != 2 {
+					t.Fatalf("inFlight() = %d within grace period, want 2", inFlight)
+				}
+
+				m.sent[5] = time.Now().Add(-nonceGapGracePeriod - time.Second)
+				reserve(t, m, client, 5)
+				reserve(t, m, client, 7)
+			},
+		},
+		{
+			name: "mined nonces dropped",
+			run: func(t *testing.T, m *nonceManager, client *fakeNonceClient) {
+				reserve(t, m, client, 5)
+				m.markSent(5)
+
+				client.pending, client.latest = 9, 9
+				reserve(t, m, client, 9)
+				if _, ok := m.sent[5]; ok {
+					t.Fatal("mined nonce still sent")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newNonceManager(common.HexToAddress("0x1000000000000000000000000000000000000001"))
+			tt.run(t, m, &fakeNonceClient{pending: 5, latest: 5})
+		})
+	}
+}
@@ -0,0 +1,351 @@
+package evm
+
+import (
+	"context"
+	"github.com/ClipFinance/relay-lib/common/types"
+	"github.com/ClipFinance/relay-lib/connectionmonitor"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// broadcastEndpoints is the number of endpoints a signed transaction is sent to, including the current one.
+	broadcastEndpoints = 3
+	// broadcastTimeout is the timeout for sending a transaction to an additional endpoint.
+	broadcastTimeout = 10 * time.Second
+)
+
+// errBroadcastUnknown is the cause of a broadcast no endpoint answered, the transaction may still be accepted.
+var errBroadcastUnknown = errors.New("transaction broadcast outcome unknown")
+
+// endpointClients keeps one client per pool endpoint other than the current one,
+// used for health probes and transaction broadcasts.
+type endpointClients struct {
+	mutex   sync.Mutex                   // Mutex for clients.
+	clients map[string]*ethclient.Client // Clients by endpoint URL.
+}
+
+// newEndpointClients creates an empty set of endpoint clients.
+//
+// Returns:
+// - *endpointClients: a new endpointClients instance.
+func newEndpointClients() *endpointClients {
+	return &endpointClients{clients: make(map[string]*ethclient.Client)}
+}
+
+// get returns the client of an endpoint, dialing it on first use.
+//
+// Parameters:
+// - ctx: the context for managing the dial.
+// - url: the endpoint URL.
+//
+// Returns:
+// - *ethclient.Client: the endpoint client.
+// - error: an error if the endpoint cannot be dialed.
+func (c *endpointClients) get(ctx context.Context, url string) (*ethclient.Client, error) {
+	c.mutex.Lock()
+	client, ok := c.clients[url]
+	c.mutex.Unlock()
+	if ok {
+		return client, nil
+	}
+
+	client, err := ethclient.DialContext(ctx, url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to dial %s", url)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// Another caller may have dialed the endpoint meanwhile.
+	if existing, ok := c.clients[url]; ok {
+		client.Close()
+		return existing, nil
+	}
+	c.clients[url] = client
+
+	return client, nil
+}
+
+// take removes the client of an endpoint from the set and returns it, dialing a new one if none is kept.
+//
+// Parameters:
+// - ctx: the context for managing the dial.
+// - url: the endpoint URL.
+//
+// Returns:
+// - *ethclient.Client: the endpoint client, now owned by the caller.
+// - error: an error if the endpoint cannot be dialed.
+func (c *endpointClients) take(ctx context.Context, url string) (*ethclient.Client, error) {
+	c.mutex.Lock()
+	client, ok := c.clients[url]
+	delete(c.clients, url)
+	c.mutex.Unlock()
+	if ok {
+		return client, nil
+	}
+
+	client, err := ethclient.DialContext(ctx, url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to dial %s", url)
+	}
+	return client, nil
+}
+
+// drop closes and removes the client of an endpoint so the next use dials it again.
+//
+// Parameters:
+// - url: the endpoint URL.
+func (c *endpointClients) drop(url string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if client, ok := c.clients[url]; ok {
+		client.Close()
+		delete(c.clients, url)
+	}
+}
+
+// closeAll closes all clients.
+func (c *endpointClients) closeAll() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for url, client := range c.clients {
+		client.Close()
+		delete(c.clients, url)
+	}
+}
+
+// endpointClient returns the client of a pool endpoint, the current client if the endpoint is in use.
+//
+// Parameters:
+// - ctx: the context for managing the dial.
+// - url: the endpoint URL.
+//
+// Returns:
+// - *ethclient.Client: the endpoint client.
+// - bool: true if the client is the current client.
+// - error: an error if the endpoint cannot be dialed.
+func (e *evm) endpointClient(ctx context.Context, url string) (*ethclient.Client, bool, error) {
+	e.clientMutex.RLock()
+	client, clientURL := e.client, e.clientURL
+	e.clientMutex.RUnlock()
+
+	if client != nil && clientURL == url {
+		return client, true, nil
+	}
+
+	client, err := e.poolClients.get(ctx, url)
+	return client, false, err
+}
+
+// failoverCandidates returns the ranked endpoints the current client may switch to.
+// Only endpoints with the subscription mode of RpcUrl qualify, so running subscriptions or polling survive the switch.
+//
+// Returns:
+// - []connectionmonitor.EndpointHealth: the ranked candidates, healthy first.
+func (e *evm) failoverCandidates() []connectionmonitor.EndpointHealth {
+	mode := types.GetSubscriptionMode(e.config.RpcUrl)
+
+	var candidates []connectionmonitor.EndpointHealth
+	for _, endpoint := range e.rpcPool.Ranked() {
+		if types.GetSubscriptionMode(endpoint.URL) == mode {
+			candidates = append(candidates, endpoint)
+		}
+	}
+	return candidates
+}
+
+// connectEndpoint connects to the first candidate endpoint that answers and makes it the current client.
+// The previous client is closed and the event handler is moved to the new client.
+//
+// Parameters:
+// - ctx: the context for managing the connection.
+// - candidates: the endpoints to try, in order.
+//
+// Returns:
+// - error: an error if none of the endpoints answers.
+func (e *evm) connectEndpoint(ctx context.Context, candidates []connectionmonitor.EndpointHealth) error {
+	lastErr := errors.New("no rpc endpoint available")
+
+	for _, candidate := range candidates {
+		start := time.Now()
+		client, err := e.poolClients.take(ctx, candidate.URL)
+		if err == nil {
+			if _, err = client.BlockNumber(ctx); err != nil {
+				client.Close()
+			}
+		}
+		e.rpcPool.RecordResult(candidate.URL, time.Since(start), err)
+
+		if err != nil {
+			e.logger.WithFields(logrus.Fields{
+				"chain": e.config.Name,
+				"url":   candidate.URL,
+			}).WithError(err).Warn("Failed to connect to RPC endpoint")
+			lastErr = err
+			continue
+		}
+
+		e.clientMutex.Lock()
+		previous, previousURL := e.client, e.clientURL
+		e.client = client
+		e.clientURL = candidate.URL
+		e.clientMutex.Unlock()
+
+		if previous != nil {
+			previous.Close()
+		}
+
+		e.eventHandlerMutex.Lock()
+		if e.eventHandler != nil {
+			e.eventHandler.UpdateClient(client)
+		}
+		e.eventHandlerMutex.Unlock()
+
+		if previousURL != "" && previousURL != candidate.URL {
+			e.logger.WithFields(logrus.Fields{
+				"chain": e.config.Name,
+				"from":  previousURL,
+				"to":    candidate.URL,
+			}).Warn("Switched RPC endpoint")
+		}
+
+		return nil
+	}
+
+	return errors.Wrap(lastErr, "failed to connect to any rpc endpoint")
+}
+
+// broadcastTransaction sends a signed transaction through the current client and the next best healthy endpoints.
+// The transaction is sent if any endpoint accepts it or already knows it. Additional endpoints are only awaited
+// if the current one does not accept the transaction.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - client: the current client.
+// - signedTx: the signed transaction.
+//
+// Returns:
+// - error: the error of the current client, or of another endpoint if the current one did not answer, if no endpoint
+// accepted the transaction; errBroadcastUnknown if no endpoint answered, the transaction may be accepted in that case.
+func (e *evm) broadcastTransaction(ctx context.Context, client *ethclient.Client, signedTx *ethtypes.Transaction) error {
+	e.clientMutex.RLock()
+	currentURL := e.clientURL
+	e.clientMutex.RUnlock()
+
+	var urls []string
+	for _, endpoint := range e.rpcPool.Ranked() {
+		if len(urls) == broadcastEndpoints-1 || !endpoint.Healthy {
+			break
+		}
+		if endpoint.URL != currentURL {
+			urls = append(urls, endpoint.URL)
+		}
+	}
+
+	results := make(chan error, len(urls))
+	for _, url := range urls {
+		go func(url string) {
+			// Detached from ctx so the broadcast completes after the current endpoint accepted the transaction.
+			broadcastCtx, cancel := context.WithTimeout(context.Background(), broadcastTimeout)
+			defer cancel()
+
+			start := time.Now()
+			endpointClient, err := e.poolClients.get(broadcastCtx, url)
+			if err == nil {
+				err = endpointClient.SendTransaction(broadcastCtx, signedTx)
+			}
+			e.rpcPool.RecordResult(url, time.Since(start), endpointError(err))
+
+			if err != nil && !isKnownTransactionError(err) {
+				e.logger.WithFields(logrus.Fields{
+					"chain":  e.config.Name,
+					"url":    url,
+					"txHash": signedTx.Hash().Hex(),
+				}).WithError(err).Debug("Failed to broadcast transaction")
+			}
+			results <- err
+		}(url)
+	}
+
+	start := time.Now()
+	primaryErr := client.SendTransaction(ctx, signedTx)
+	e.rpcPool.RecordResult(currentURL, time.Since(start), endpointError(primaryErr))
+
+	if primaryErr == nil || isKnownTransactionError(primaryErr) {
+		return nil
+	}
+
+	// The additional endpoints answer within broadcastTimeout, they may accept the transaction after ctx is done.
+	var broadcastErr error
+	for range urls {
+		err := <-results
+		if err == nil || isKnownTransactionError(err) {
+			return nil
+		}
+		if broadcastErr == nil || endpointError(err) == nil {
+			broadcastErr = err
+		}
+	}
+
+	// Without a node rejecting the transaction, it may have been accepted by an endpoint that did not answer.
+	if endpointError(primaryErr) != nil && (broadcastErr == nil || endpointError(broadcastErr) != nil) {
+		return errors.Wrap(errBroadcastUnknown, primaryErr.Error())
+	}
+
+	// A node rejecting the transaction explains the failure better than an unreachable one.
+	if endpointError(primaryErr) != nil {
+		return broadcastErr
+	}
+
+	return primaryErr
+}
+
+// ProbeHead returns the latest block number of a pool endpoint.
+// It implements connectionmonitor.EndpointProber.
+//
+// Parameters:
+// - ctx: the context for managing the probe.
+// - url: the endpoint URL.
+//
+// Returns:
+// - uint64: the latest block number.
+// - error: an error if the endpoint cannot be dialed or does not answer.
+func (w *evmConnectionManager) ProbeHead(ctx context.Context, url string) (uint64, error) {
+	client, current, err := w.chain.endpointClient(ctx, url)
+	if err != nil {
+		return 0, err
+	}
+
+	head, err := client.BlockNumber(ctx)
+	if err != nil && !current {
+		// Redial on the next probe, a broken WebSocket connection does not recover by itself.
+		w.chain.poolClients.drop(url)
+	}
+
+	return head, err
+}
+
+// endpointError returns the error if it is caused by the endpoint rather than rejected by the node.
+func endpointError(err error) error {
+	var rpcErr rpc.Error
+	if err == nil || errors.As(err, &rpcErr) {
+		return nil
+	}
+	return err
+}
+
+// isKnownTransactionError reports whether the node rejected the transaction because it already has it.
+func isKnownTransactionError(err error) bool {
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "already known") || strings.Contains(message, "known transaction")
+}
@@ -3,17 +3,22 @@ package evm
 import (
 	"context"
 	"github.com/ClipFinance/relay-lib/chains/evm/generated"
+	"github.com/ClipFinance/relay-lib/chains/evm/signer"
 	"github.com/ClipFinance/relay-lib/chains/evm/utils"
 	"github.com/ClipFinance/relay-lib/common/types"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"math/big"
 	"strings"
 )
 
 // SendAsset sends an asset (native or token) based on the provided transaction intent.
+// The transaction is simulated against the pending block before it is signed, and sent from the next
+// selected wallet if the sending wallet cannot pay for the transfer and its gas.
 //
 // Parameters:
 // - ctx: the context for managing the request.
@@ -21,7 +26,8 @@ import (
 //
 // Returns:
 // - *types.Transaction: the transaction details.
-// - error: an error if the client is not initialized or if the transaction fails.
+// - error: an error if the client is not initialized or if the transaction fails, a *types.SendError with
+// the intent sub-status if the simulation reverts or no wallet holds enough of the token.
 func (e *evm) SendAsset(ctx context.Context, intent *types.Intent) (*types.Transaction, error) {
 	e.clientMutex.RLock()
 	client := e.client
@@ -31,24 +37,63 @@ func (e *evm) SendAsset(ctx context.Context, intent *types.Intent) (*types.Trans
 		return nil, errors.New("client not initialized")
 	}
 
-	nonce, err := client.PendingNonceAt(ctx, e.signer.Address())
+	wallets, err := e.selectWallets(ctx, client, intent.ToToken, intent.ToAmount)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get nonce")
+		return nil, sendErrorOf(errors.Wrap(err, "failed to select wallet"))
 	}
 
-	var tx *ethtypes.Transaction
-	if intent.ToToken == utils.ZeroAddress {
-		tx, err = e.sendNativeAsset(ctx, intent, nonce)
-	} else {
-		tx, err = e.sendToken(ctx, intent, nonce)
-	}
-	if err != nil {
-		return nil, err
+	var (
+		w     *wallet
+		nonce uint64
+		tx    *ethtypes.Transaction
+	)
+	for i := range wallets {
+		w = wallets[i]
+
+		nonce, err = w.nonceManager.reserve(ctx, client)
+		if err != nil {
+			if i < len(wallets)-1 {
+				e.logger.WithFields(logrus.Fields{
+					"chain":  e.config.Name,
+					"wallet": w.signer.Address().Hex(),
+				}).WithError(err).Warn("Failed to get wallet nonce, trying next wallet")
+				continue
+			}
+			return nil, errors.Wrap(err, "failed to get nonce")
+		}
+
+		if intent.ToToken == utils.ZeroAddress {
+			tx, err = e.sendNativeAsset(ctx, client, w, intent, nonce)
+		} else {
+			tx, err = e.sendToken(ctx, client, w, intent, nonce)
+		}
+		if err == nil {
+			break
+		}
+
+		// The transaction may still be mined, reconcile releases the nonce if the node never sees it.
+		if errors.Is(err, errBroadcastUnknown) {
+			w.nonceManager.markSent(nonce)
+			return nil, sendErrorOf(err)
+		}
+		e.releaseNonce(w, nonce)
+
+		// The wallet holds the transferred amount but not the gas on top of it.
+		if errors.Is(err, ErrInsufficientBalance) && i < len(wallets)-1 {
+			e.logger.WithFields(logrus.Fields{
+				"chain":  e.config.Name,
+				"wallet": w.signer.Address().Hex(),
+			}).WithError(err).Warn("Wallet cannot pay for transfer, trying next wallet")
+			continue
+		}
+		return nil, sendErrorOf(err)
 	}
 
+	w.nonceManager.markSent(nonce)
+
 	return &types.Transaction{
 		Hash:       tx.Hash().Hex(),
-		From:       e.signer.Address().Hex(),
+		From:       w.signer.Address().Hex(),
 		To:         intent.RecipientAddress,
 		FromAmount: intent.FromAmount.String(),
 		ToAmount:   intent.ToAmount.String(),
@@ -59,36 +104,44 @@ func (e *evm) SendAsset(ctx context.Context, intent *types.Intent) (*types.Trans
 	}, nil
 }
 
-// sendNativeAsset sends a native asset (Ether) based on the provided transaction intent.
+// sendNativeAsset sends a native asset (Ether) from a wallet based on the provided transaction intent.
 //
 // Parameters:
 // - ctx: the context for managing the request.
+// - client: the client the transaction is simulated with.
+// - w: the wallet sending the transaction.
 // - intent: the transaction intent containing details of the asset transfer.
 // - nonce: the nonce for the transaction.
 //
 // Returns:
 // - *ethtypes.Transaction: the transaction details.
 // - error: an error if the transaction preparation or sending fails.
-func (e *evm) sendNativeAsset(ctx context.Context, intent *types.Intent, nonce uint64) (*ethtypes.Transaction, error) {
-	tx, err := e.prepareTransaction(ctx, nonce, intent.RecipientAddress, intent.ToAmount, nil)
+func (e *evm) sendNativeAsset(ctx context.Context, client *ethclient.Client, w *wallet, intent *types.Intent, nonce uint64) (*ethtypes.Transaction, error) {
+	tx, err := e.prepareTransaction(ctx, w.signer.Address(), nonce, intent.RecipientAddress, intent.ToAmount, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	return e.signAndSendTransaction(ctx, tx)
+	if err := e.simulateTransaction(ctx, client, w.signer.Address(), tx); err != nil {
+		return nil, err
+	}
+
+	return e.signAndSendTransaction(ctx, w.signer, tx)
 }
 
-// sendToken sends a token based on the provided transaction intent.
+// sendToken sends a token from a wallet based on the provided transaction intent.
 //
 // Parameters:
 // - ctx: the context for managing the request.
+// - client: the client the transaction is simulated with.
+// - w: the wallet sending the transaction.
 // - intent: the transaction intent containing details of the asset transfer.
 // - nonce: the nonce for the transaction.
 //
 // Returns:
 // - *ethtypes.Transaction: the transaction details.
 // - error: an error if the token ABI parsing, data packing, transaction preparation, or sending fails.
-func (e *evm) sendToken(ctx context.Context, intent *types.Intent, nonce uint64) (*ethtypes.Transaction, error) {
+func (e *evm) sendToken(ctx context.Context, client *ethclient.Client, w *wallet, intent *types.Intent, nonce uint64) (*ethtypes.Transaction, error) {
 	tokenAbi, err := abi.JSON(strings.NewReader(generated.ERC20ABI))
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to parse token ABI")
@@ -99,18 +152,23 @@ func (e *evm) sendToken(ctx context.Context, intent *types.Intent, nonce uint64)
 		return nil, errors.Wrap(err, "failed to pack transfer data")
 	}
 
-	tx, err := e.prepareTransaction(ctx, nonce, intent.ToToken, big.NewInt(0), data)
+	tx, err := e.prepareTransaction(ctx, w.signer.Address(), nonce, intent.ToToken, big.NewInt(0), data)
 	if err != nil {
 		return nil, err
 	}
 
-	return e.signAndSendTransaction(ctx, tx)
+	if err := e.simulateTransaction(ctx, client, w.signer.Address(), tx); err != nil {
+		return nil, err
+	}
+
+	return e.signAndSendTransaction(ctx, w.signer, tx)
 }
 
 // prepareTransaction prepares a transaction with the given parameters.
 //
 // Parameters:
 // - ctx: the context for managing the request.
+// - from: the account sending the transaction.
 // - nonce: the nonce for the transaction.
 // - toAddress: the recipient address of the transaction.
 // - value: the amount of Ether to send with the transaction.
@@ -119,11 +177,11 @@ func (e *evm) sendToken(ctx context.Context, intent *types.Intent, nonce uint64)
 // Returns:
 // - *ethtypes.Transaction: the prepared transaction.
 // - error: an error if the gas estimation, gas price retrieval, or client initialization fails.
-func (e *evm) prepareTransaction(ctx context.Context, nonce uint64, toAddress string, value *big.Int, data []byte) (*ethtypes.Transaction, error) {
-	estimatedGas, err := e.EstimateGas(ctx, toAddress, value, data)
+func (e *evm) prepareTransaction(ctx context.Context, from common.Address, nonce uint64, toAddress string, value *big.Int, data []byte) (*ethtypes.Transaction, error) {
+	estimatedGas, err := e.estimateGas(ctx, from, toAddress, value, data)
 	if err != nil {
 		e.logger.WithField("chain", e.config.Name).WithError(err).Warn("Failed to estimate gas")
-		return nil, errors.Wrap(err, "failed to estimate gas")
+		return nil, errors.Wrap(decodeCallError(err), "failed to estimate gas")
 	}
 
 	gasLimit := uint64(float64(estimatedGas) * 1.1)
@@ -165,7 +223,7 @@ func (e *evm) prepareTransaction(ctx context.Context, nonce uint64, toAddress st
 		}), nil
 	}
 
-	gasPrice, err := e.estimateLegacyGasPrice(ctx, to.Hex(), value, data)
+	gasPrice, err := e.estimateLegacyGasPrice(ctx, from, to.Hex(), value, data)
 	if err != nil {
 		e.logger.WithField("chain", e.config.Name).WithError(err).Error("Failed to get gas price")
 		return nil, errors.Wrap(err, "failed to get gas price")
@@ -193,37 +251,35 @@ func (e *evm) prepareTransaction(ctx context.Context, nonce uint64, toAddress st
 	), nil
 }
 
-// signAndSendTransaction signs and sends the prepared transaction.
+// signAndSendTransaction signs the prepared transaction with the signer of the sending wallet
+// and broadcasts it to the best RPC endpoints.
 //
 // Parameters:
 // - ctx: the context for managing the request.
+// - txSigner: the signer of the wallet sending the transaction.
 // - tx: the prepared transaction to be signed and sent.
 //
 // Returns:
 // - *ethtypes.Transaction: the signed and sent transaction.
 // - error: an error if the client or signer is not initialized, or if the signing or sending fails.
-func (e *evm) signAndSendTransaction(ctx context.Context, tx *ethtypes.Transaction) (*ethtypes.Transaction, error) {
+func (e *evm) signAndSendTransaction(ctx context.Context, txSigner signer.Signer, tx *ethtypes.Transaction) (*ethtypes.Transaction, error) {
 	e.clientMutex.RLock()
 	client := e.client
 	e.clientMutex.RUnlock()
 
-	e.signerMutex.RLock()
-	signer := e.signer
-	e.signerMutex.RUnlock()
-
-	if client == nil || signer == nil {
+	if client == nil || txSigner == nil {
 		return nil, errors.New("client or signer not initialized")
 	}
 
 	chainID := big.NewInt(0).SetUint64(e.config.ChainID)
 
-	signedTx, err := signer.SignTx(tx, chainID)
+	signedTx, err := txSigner.SignTx(tx, chainID)
 	if err != nil {
 		e.logger.WithError(err).Error("Failed to sign transaction")
 		return nil, errors.Wrap(err, "failed to sign transaction")
 	}
 
-	if err = client.SendTransaction(ctx, signedTx); err != nil {
+	if err = e.broadcastTransaction(ctx, client, signedTx); err != nil {
 		e.logger.WithError(err).Error("Failed to send transaction")
 		return nil, errors.Wrap(err, "failed to send transaction")
 	}
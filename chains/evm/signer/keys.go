@@ -0,0 +1,177 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"github.com/ClipFinance/relay-lib/common/types"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"github.com/tyler-smith/go-bip39"
+	"golang.org/x/text/unicode/norm"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// NewKeystoreSigner creates a signer with the key of a go-ethereum V3 JSON keystore file.
+// The decrypted key is only kept by the signer.
+//
+// Parameters:
+// - config: the keystore configuration.
+//
+// Returns:
+// - Signer: a new signer instance.
+// - error: an error if the keystore cannot be read or decrypted.
+func NewKeystoreSigner(config *types.KeystoreConfig) (Signer, error) {
+	keyJSON, err := os.ReadFile(config.Path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read keystore file")
+	}
+
+	passphrase, err := keystorePassphrase(config)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt keystore")
+	}
+
+	return NewSigner(key.PrivateKey)
+}
+
+// keystorePassphrase reads the passphrase of a keystore from the environment or from a file.
+//
+// Parameters:
+// - config: the keystore configuration.
+//
+// Returns:
+// - string: the passphrase.
+// - error: an error if no passphrase source is configured or the passphrase file cannot be read.
+func keystorePassphrase(config *types.KeystoreConfig) (string, error) {
+	switch {
+	case config.PassphraseEnv != "":
+		passphrase, ok := os.LookupEnv(config.PassphraseEnv)
+		if !ok {
+			return "", errors.Errorf("keystore passphrase variable %s is not set", config.PassphraseEnv)
+		}
+		return passphrase, nil
+	case config.PassphraseFile != "":
+		passphrase, err := os.ReadFile(config.PassphraseFile)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to read keystore passphrase file")
+		}
+		return strings.TrimRight(string(passphrase), "\r\n"), nil
+	default:
+		return "", errors.New("keystore passphrase source is not configured")
+	}
+}
+
+// NewMnemonicSigner creates a signer with the key derived from a BIP-39 mnemonic along a BIP-44 derivation path.
+// The seed and intermediate keys are cleared once the key is derived, which is only kept by the signer.
+//
+// Parameters:
+// - config: the mnemonic configuration.
+//
+// Returns:
+// - Signer: a new signer instance.
+// - error: an error if the mnemonic is not a valid BIP-39 English phrase or the derivation path is invalid.
+func NewMnemonicSigner(config *types.MnemonicConfig) (Signer, error) {
+	mnemonic := config.Mnemonic
+	if mnemonic == "" && config.MnemonicEnv != "" {
+		mnemonic = os.Getenv(config.MnemonicEnv)
+	}
+
+	// BIP-39 seeds are derived from the NFKD forms of the mnemonic and the passphrase.
+	mnemonic = strings.Join(strings.Fields(norm.NFKD.String(mnemonic)), " ")
+	if _, err := bip39.EntropyFromMnemonic(mnemonic); err != nil {
+		return nil, errors.Wrap(err, "invalid mnemonic")
+	}
+
+	path := accounts.DefaultBaseDerivationPath
+	if config.DerivationPath != "" {
+		var err error
+		path, err = accounts.ParseDerivationPath(config.DerivationPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse derivation path")
+		}
+	}
+
+	seed := bip39.NewSeed(mnemonic, norm.NFKD.String(config.Passphrase))
+	defer clear(seed)
+
+	privateKey, err := deriveKey(seed, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSigner(privateKey)
+}
+
+// deriveKey derives the BIP-32 private key of a derivation path from a seed.
+//
+// Parameters:
+// - seed: the BIP-32 seed.
+// - path: the derivation path.
+//
+// Returns:
+// - *ecdsa.PrivateKey: the derived private key.
+// - error: an error if the path leads to an invalid key.
+func deriveKey(seed []byte, path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	curveOrder := crypto.S256().Params().N
+
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	extended := mac.Sum(nil)
+	defer func() { clear(extended) }()
+
+	key, chainCode := extended[:32], extended[32:]
+	if k := new(big.Int).SetBytes(key); k.Sign() == 0 || k.Cmp(curveOrder) >= 0 {
+		return nil, errors.New("invalid master key")
+	}
+
+	for _, index := range path {
+		data := make([]byte, 0, 37)
+		if index >= 0x80000000 {
+			data = append(data, 0)
+			data = append(data, key...)
+		} else {
+			parent, err := crypto.ToECDSA(key)
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid parent key")
+			}
+			data = append(data, crypto.CompressPubkey(&parent.PublicKey)...)
+		}
+		data = binary.BigEndian.AppendUint32(data, index)
+
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		clear(data)
+		child := mac.Sum(nil)
+
+		tweak := new(big.Int).SetBytes(child[:32])
+		if tweak.Cmp(curveOrder) >= 0 {
+			clear(child)
+			return nil, errors.Errorf("invalid key at derivation index %d", index)
+		}
+		childKey := tweak.Add(tweak, new(big.Int).SetBytes(key))
+		childKey.Mod(childKey, curveOrder)
+		if childKey.Sign() == 0 {
+			clear(child)
+			return nil, errors.Errorf("invalid key at derivation index %d", index)
+		}
+
+		clear(extended)
+		extended = append(math.PaddedBigBytes(childKey, 32), child[32:]...)
+		key, chainCode = extended[:32], extended[32:]
+		clear(child)
+	}
+
+	return crypto.ToECDSA(key)
+}
@@ -0,0 +1,70 @@
+package signer
+
+import (
+	"github.com/ClipFinance/relay-lib/common/types"
+	"github.com/ethereum/go-ethereum/common"
+	"testing"
+)
+
+func TestNewMnemonicSigner(t *testing.T) {
+	const mnemonic = "test test test test test test test test test test test junk"
+
+	// The address derived with the NFKD form of the passphrase, which composed and decomposed forms share.
+	decomposed, err := NewMnemonicSigner(&types.MnemonicConfig{Mnemonic: mnemonic, Passphrase: "cafe\u0301"})
+	if err != nil {
+		t.Fatalf("NewMnemonicSigner() error = %v", err)
+	}
+	if decomposed.Address() == common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266") {
+		t.Fatal("passphrase ignored")
+	}
+
+	tests := []struct {
+		name        string
+		config      types.MnemonicConfig
+		wantAddress common.Address
+		wantErr     bool
+	}{
+		{
+			name:        "default path",
+			config:      types.MnemonicConfig{Mnemonic: mnemonic},
+			wantAddress: common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"),
+		},
+		{
+			name:        "derivation path",
+			config:      types.MnemonicConfig{Mnemonic: mnemonic, DerivationPath: "m/44'/60'/0'/0/1"},
+			wantAddress: common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"),
+		},
+		{
+			name:        "extra whitespace",
+			config:      types.MnemonicConfig{Mnemonic: "  test test test test test test\ttest test test test test junk\n"},
+			wantAddress: common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"),
+		},
+		{
+			name:        "composed passphrase",
+			config:      types.MnemonicConfig{Mnemonic: mnemonic, Passphrase: "caf\u00e9"},
+			wantAddress: decomposed.Address(),
+		},
+		{name: "words outside the wordlist", config: types.MnemonicConfig{Mnemonic: "foo bar baz qux a b c d e f g h"}, wantErr: true},
+		{name: "invalid checksum", config: types.MnemonicConfig{Mnemonic: "test test test test test test test test test test test test"}, wantErr: true},
+		{name: "invalid length", config: types.MnemonicConfig{Mnemonic: "test test test test test test test test test test junk"}, wantErr: true},
+		{name: "empty", config: types.MnemonicConfig{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewMnemonicSigner(&tt.config)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NewMnemonicSigner() = %s, want error", s.Address().Hex())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewMnemonicSigner() error = %v", err)
+			}
+			if s.Address() != tt.wantAddress {
+				t.Fatalf("Address() = %s, want %s", s.Address().Hex(), tt.wantAddress.Hex())
+			}
+		})
+	}
+}
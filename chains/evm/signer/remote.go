@@ -0,0 +1,340 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ClipFinance/relay-lib/common/types"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/pkg/errors"
+	"math/big"
+	"time"
+)
+
+// remoteRequestTimeout is the timeout of a request to the signing service.
+const remoteRequestTimeout = 30 * time.Second
+
+// transactionArgs are the transaction fields of eth_signTransaction.
+type transactionArgs struct {
+	From                 common.Address       `json:"from"`
+	To                   *common.Address      `json:"to,omitempty"`
+	Gas                  hexutil.Uint64       `json:"gas"`
+	GasPrice             *hexutil.Big         `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big         `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big         `json:"maxPriorityFeePerGas,omitempty"`
+	Value                *hexutil.Big         `json:"value"`
+	Nonce                hexutil.Uint64       `json:"nonce"`
+	Data                 hexutil.Bytes        `json:"data"`
+	AccessList           *ethtypes.AccessList `json:"accessList,omitempty"`
+	ChainID              *hexutil.Big         `json:"chainId,omitempty"`
+	Type                 hexutil.Uint64       `json:"type"`
+}
+
+// newTransactionArgs returns the eth_signTransaction fields of a transaction.
+func newTransactionArgs(from common.Address, tx *ethtypes.Transaction, chainID *big.Int) (*transactionArgs, error) {
+	args := &transactionArgs{
+		From:    from,
+		To:      tx.To(),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   (*hexutil.Big)(tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Data:    tx.Data(),
+		ChainID: (*hexutil.Big)(chainID),
+		Type:    hexutil.Uint64(tx.Type()),
+	}
+
+	switch tx.Type() {
+	case ethtypes.LegacyTxType:
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	case ethtypes.AccessListTxType:
+		accessList := tx.AccessList()
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+		args.AccessList = &accessList
+	case ethtypes.DynamicFeeTxType:
+		accessList := tx.AccessList()
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+		args.AccessList = &accessList
+	default:
+		return nil, errors.Errorf("unsupported transaction type %d", tx.Type())
+	}
+
+	return args, nil
+}
+
+// toTransaction returns the unsigned transaction described by the fields.
+func (args *transactionArgs) toTransaction() (*ethtypes.Transaction, error) {
+	value := new(big.Int)
+	if args.Value != nil {
+		value = args.Value.ToInt()
+	}
+
+	var accessList ethtypes.AccessList
+	if args.AccessList != nil {
+		accessList = *args.AccessList
+	}
+
+	switch {
+	case args.MaxFeePerGas != nil:
+		if args.ChainID == nil || args.MaxPriorityFeePerGas == nil {
+			return nil, errors.New("dynamic fee transaction needs chainId and maxPriorityFeePerGas")
+		}
+		return ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+			ChainID:    args.ChainID.ToInt(),
+			Nonce:      uint64(args.Nonce),
+			GasTipCap:  args.MaxPriorityFeePerGas.ToInt(),
+			GasFeeCap:  args.MaxFeePerGas.ToInt(),
+			Gas:        uint64(args.Gas),
+			To:         args.To,
+			Value:      value,
+			Data:       args.Data,
+			AccessList: accessList,
+		}), nil
+	case args.GasPrice == nil:
+		return nil, errors.New("transaction needs gasPrice or maxFeePerGas")
+	case args.AccessList != nil:
+		if args.ChainID == nil {
+			return nil, errors.New("access list transaction needs chainId")
+		}
+		return ethtypes.NewTx(&ethtypes.AccessListTx{
+			ChainID:    args.ChainID.ToInt(),
+			Nonce:      uint64(args.Nonce),
+			GasPrice:   args.GasPrice.ToInt(),
+			Gas:        uint64(args.Gas),
+			To:         args.To,
+			Value:      value,
+			Data:       args.Data,
+			AccessList: accessList,
+		}), nil
+	default:
+		return ethtypes.NewTx(&ethtypes.LegacyTx{
+			Nonce:    uint64(args.Nonce),
+			GasPrice: args.GasPrice.ToInt(),
+			Gas:      uint64(args.Gas),
+			To:       args.To,
+			Value:    value,
+			Data:     args.Data,
+		}), nil
+	}
+}
+
+// remoteSigner is a Signer delegating to an external signing service over JSON-RPC.
+type remoteSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+// NewRemoteSigner creates a signer delegating to the signing service of the configuration.
+// The service keeps the key and must answer eth_signTransaction, eth_sign and eth_signTypedData like Web3Signer.
+//
+// Parameters:
+// - config: the signing service configuration.
+//
+// Returns:
+// - Signer: a new signer instance.
+// - error: an error if the service cannot be reached or does not manage the account.
+func NewRemoteSigner(config *types.RemoteSignerConfig) (Signer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteRequestTimeout)
+	defer cancel()
+
+	var options []rpc.ClientOption
+	if config.AuthToken != "" {
+		options = append(options, rpc.WithHeader("Authorization", "Bearer "+config.AuthToken))
+	}
+
+	client, err := rpc.DialOptions(ctx, config.URL, options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to remote signer")
+	}
+
+	var accounts []common.Address
+	if err := client.CallContext(ctx, &accounts, "eth_accounts"); err != nil {
+		client.Close()
+		return nil, errors.Wrap(err, "failed to get remote signer accounts")
+	}
+
+	if config.Address == "" {
+		if len(accounts) == 0 {
+			client.Close()
+			return nil, errors.New("remote signer has no accounts")
+		}
+		return &remoteSigner{client: client, address: accounts[0]}, nil
+	}
+
+	if !common.IsHexAddress(config.Address) {
+		client.Close()
+		return nil, errors.Errorf("invalid remote signer address %q", config.Address)
+	}
+
+	address := common.HexToAddress(config.Address)
+	for _, account := range accounts {
+		if account == address {
+			return &remoteSigner{client: client, address: address}, nil
+		}
+	}
+
+	client.Close()
+	return nil, errors.Errorf("remote signer does not manage account %s", address.Hex())
+}
+
+// Sign signs the given data with eth_sign and returns the signature.
+//
+// Parameters:
+// - data: the data to be signed.
+//
+// Returns:
+// - []byte: the signature.
+// - error: an error if the signing process fails or the signature is not made by the signer's account.
+func (s *remoteSigner) Sign(data []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteRequestTimeout)
+	defer cancel()
+
+	var signature hexutil.Bytes
+	if err := s.client.CallContext(ctx, &signature, "eth_sign", s.address, hexutil.Bytes(data)); err != nil {
+		return nil, errors.Wrap(err, "failed to sign message")
+	}
+
+	if len(signature) != crypto.SignatureLength {
+		return nil, errors.Errorf("invalid signature length %d", len(signature))
+	}
+	if signature[64] < 27 {
+		signature[64] += 27 // Transform V from 0/1 to 27/28 according to the yellow paper
+	}
+
+	signerAddress, err := recoverAddress(accounts.TextHash(data), signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to recover signer of message")
+	}
+	if signerAddress != s.address {
+		return nil, errors.New("message signed by another account")
+	}
+
+	return signature, nil
+}
+
+// SignTypedData signs EIP-712 typed data with eth_signTypedData and returns the signature.
+//
+// Parameters:
+// - domain: the signing domain.
+// - types: the type definitions of the message.
+// - message: the message.
+//
+// Returns:
+// - []byte: the signature.
+// - error: an error if the signing process fails or the signature is not made by the signer's account.
+func (s *remoteSigner) SignTypedData(domain apitypes.TypedDataDomain, types apitypes.Types, message apitypes.TypedDataMessage) ([]byte, error) {
+	typedData, err := newTypedData(domain, types, encodeTypedDataMessage(message))
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := typedDataHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), remoteRequestTimeout)
+	defer cancel()
+
+	var signature hexutil.Bytes
+	if err := s.client.CallContext(ctx, &signature, "eth_signTypedData", s.address, typedData); err != nil {
+		return nil, errors.Wrap(err, "failed to sign typed data")
+	}
+
+	if len(signature) != crypto.SignatureLength {
+		return nil, errors.Errorf("invalid signature length %d", len(signature))
+	}
+	if signature[64] < 27 {
+		signature[64] += 27 // Transform V from 0/1 to 27/28 according to the yellow paper
+	}
+
+	signerAddress, err := recoverAddress(hash, signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to recover signer of typed data")
+	}
+	if signerAddress != s.address {
+		return nil, errors.New("typed data signed by another account")
+	}
+
+	return signature, nil
+}
+
+// SignTx signs the given transaction with eth_signTransaction and returns the signed transaction.
+//
+// Parameters:
+// - tx: the transaction to be signed.
+// - chainID: the chain ID for the transaction.
+//
+// Returns:
+// - *ethtypes.Transaction: the signed transaction.
+// - error: an error if the signing process fails or the service signed another transaction.
+func (s *remoteSigner) SignTx(tx *ethtypes.Transaction, chainID *big.Int) (*ethtypes.Transaction, error) {
+	args, err := newTransactionArgs(s.address, tx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), remoteRequestTimeout)
+	defer cancel()
+
+	var result json.RawMessage
+	if err := s.client.CallContext(ctx, &result, "eth_signTransaction", args); err != nil {
+		return nil, errors.Wrap(err, "failed to sign transaction")
+	}
+
+	raw, err := decodeSignedTransaction(result)
+	if err != nil {
+		return nil, err
+	}
+
+	signedTx := new(ethtypes.Transaction)
+	if err := signedTx.UnmarshalBinary(raw); err != nil {
+		return nil, errors.Wrap(err, "failed to decode signed transaction")
+	}
+
+	// The service must sign exactly the requested transaction with the signer's account.
+	txSigner := ethtypes.LatestSignerForChainID(chainID)
+	if txSigner.Hash(signedTx) != txSigner.Hash(tx) {
+		return nil, errors.New("remote signer signed a different transaction")
+	}
+	sender, err := ethtypes.Sender(txSigner, signedTx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to recover transaction sender")
+	}
+	if sender != s.address {
+		return nil, errors.New("transaction signed by another account")
+	}
+
+	return signedTx, nil
+}
+
+// Address returns the signer's address.
+//
+// Returns:
+// - common.Address: the signer's address.
+func (s *remoteSigner) Address() common.Address {
+	return s.address
+}
+
+// decodeSignedTransaction returns the raw signed transaction of an eth_signTransaction result,
+// either the raw transaction itself (Web3Signer) or an object holding it (Geth, Clef).
+func decodeSignedTransaction(result json.RawMessage) ([]byte, error) {
+	var raw hexutil.Bytes
+	if err := json.Unmarshal(result, &raw); err == nil {
+		return raw, nil
+	}
+
+	var signed struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}
+	if err := json.Unmarshal(result, &signed); err != nil || len(signed.Raw) == 0 {
+		return nil, errors.New("unexpected eth_signTransaction result")
+	}
+
+	return signed.Raw, nil
+}
@@ -0,0 +1,205 @@
+package signer
+
+import (
+	"github.com/ClipFinance/relay-lib/common/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var (
+	testChainID   = big.NewInt(1)
+	testRecipient = common.HexToAddress("0x7000000000000000000000000000000000000007")
+)
+
+// impostorSigner claims an account but signs with the key of another one.
+type impostorSigner struct {
+	Signer
+	address common.Address
+}
+
+// Address returns the claimed account.
+func (s *impostorSigner) Address() common.Address {
+	return s.address
+}
+
+// tamperingSigner signs another transaction than the requested one.
+type tamperingSigner struct {
+	Signer
+}
+
+// SignTx signs the transaction with its value raised.
+func (s *tamperingSigner) SignTx(tx *ethtypes.Transaction, chainID *big.Int) (*ethtypes.Transaction, error) {
+	return s.Signer.SignTx(ethtypes.NewTx(&ethtypes.LegacyTx{
+		Nonce:    tx.Nonce(),
+		GasPrice: tx.GasPrice(),
+		Gas:      tx.Gas(),
+		To:       tx.To(),
+		Value:    new(big.Int).Add(tx.Value(), common.Big1),
+		Data:     tx.Data(),
+	}), chainID)
+}
+
+// newTestSigner creates a signer with the given hex private key.
+func newTestSigner(t *testing.T, key string) Signer {
+	t.Helper()
+
+	privateKey, err := crypto.HexToECDSA(key)
+	if err != nil {
+		t.Fatalf("HexToECDSA() error = %v", err)
+	}
+	s, err := NewSigner(privateKey)
+	if err != nil {
+		t.Fatalf("NewSigner() error = %v", err)
+	}
+
+	return s
+}
+
+// newStandInRemoteSigner starts a signing server backed by the given signer and connects a remote signer to it.
+func newStandInRemoteSigner(t *testing.T, served Signer) Signer {
+	t.Helper()
+
+	handler, err := NewSigningServer(served)
+	if err != nil {
+		t.Fatalf("NewSigningServer() error = %v", err)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	remote, err := NewRemoteSigner(&types.RemoteSignerConfig{URL: server.URL})
+	if err != nil {
+		t.Fatalf("NewRemoteSigner() error = %v", err)
+	}
+
+	return remote
+}
+
+func TestRemoteSigner(t *testing.T) {
+	local := newTestSigner(t, "b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	other := newTestSigner(t, "8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+
+	legacyTx := ethtypes.NewTx(&ethtypes.LegacyTx{
+		Nonce:    3,
+		GasPrice: big.NewInt(20_000_000_000),
+		Gas:      21_000,
+		To:       &testRecipient,
+		Value:    big.NewInt(1_000_000),
+	})
+	dynamicFeeTx := ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+		ChainID:   testChainID,
+		Nonce:     4,
+		GasTipCap: big.NewInt(1_000_000_000),
+		GasFeeCap: big.NewInt(30_000_000_000),
+		Gas:       60_000,
+		To:        &testRecipient,
+		Value:     big.NewInt(2_000_000),
+		Data:      []byte{0xa9, 0x05, 0x9c, 0xbb},
+	})
+
+	domain := apitypes.TypedDataDomain{
+		Name:              "Relay",
+		Version:           "1",
+		ChainId:           math.NewHexOrDecimal256(1),
+		VerifyingContract: testRecipient.Hex(),
+	}
+	typeDefinitions := apitypes.Types{
+		"Order": {
+			{Name: "maker", Type: "address"},
+			{Name: "amount", Type: "uint256"},
+			{Name: "memo", Type: "bytes"},
+		},
+	}
+	message := apitypes.TypedDataMessage{
+		"maker":  testRecipient.Hex(),
+		"amount": new(big.Int).Add(new(big.Int).Exp(big.NewInt(10), big.NewInt(24), nil), common.Big1),
+		"memo":   []byte{0x01, 0x02},
+	}
+
+	signTx := func(tx *ethtypes.Transaction) func(Signer) ([]byte, error) {
+		return func(s Signer) ([]byte, error) {
+			signedTx, err := s.SignTx(tx, testChainID)
+			if err != nil {
+				return nil, err
+			}
+			return signedTx.MarshalBinary()
+		}
+	}
+	signMessage := func(s Signer) ([]byte, error) {
+		return s.Sign([]byte("relay"))
+	}
+	signTypedData := func(s Signer) ([]byte, error) {
+		return s.SignTypedData(domain, typeDefinitions, message)
+	}
+
+	tests := []struct {
+		name    string
+		served  Signer
+		sign    func(Signer) ([]byte, error)
+		wantErr string
+	}{
+		{name: "legacy transaction", served: local, sign: signTx(legacyTx)},
+		{name: "dynamic fee transaction", served: local, sign: signTx(dynamicFeeTx)},
+		{name: "message", served: local, sign: signMessage},
+		{name: "typed data", served: local, sign: signTypedData},
+		{
+			name:    "transaction of wrong account",
+			served:  &impostorSigner{Signer: other, address: local.Address()},
+			sign:    signTx(dynamicFeeTx),
+			wantErr: "transaction signed by another account",
+		},
+		{
+			name:    "message of wrong account",
+			served:  &impostorSigner{Signer: other, address: local.Address()},
+			sign:    signMessage,
+			wantErr: "message signed by another account",
+		},
+		{
+			name:    "typed data of wrong account",
+			served:  &impostorSigner{Signer: other, address: local.Address()},
+			sign:    signTypedData,
+			wantErr: "typed data signed by another account",
+		},
+		{
+			name:    "different transaction",
+			served:  &tamperingSigner{Signer: local},
+			sign:    signTx(legacyTx),
+			wantErr: "remote signer signed a different transaction",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote := newStandInRemoteSigner(t, tt.served)
+			if remote.Address() != local.Address() {
+				t.Fatalf("Address() = %s, want %s", remote.Address().Hex(), local.Address().Hex())
+			}
+
+			got, err := tt.sign(remote)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+
+			// Signatures are deterministic, the service must produce the local signature.
+			want, err := tt.sign(local)
+			if err != nil {
+				t.Fatalf("local error = %v", err)
+			}
+			if common.Bytes2Hex(got) != common.Bytes2Hex(want) {
+				t.Fatalf("signed %x, want %x", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package signer
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/pkg/errors"
+	"net/http"
+)
+
+// signingService serves the eth_accounts, eth_sign, eth_signTypedData and eth_signTransaction methods of a signing service.
+type signingService struct {
+	signer Signer
+}
+
+// NewSigningServer creates a JSON-RPC handler answering eth_accounts, eth_sign, eth_signTypedData and eth_signTransaction like
+// Web3Signer, backed by the given signer. It stands in for a remote signing service in tests and local setups,
+// for example behind httptest.NewServer.
+//
+// Parameters:
+// - signer: the signer holding the key.
+//
+// Returns:
+// - http.Handler: the JSON-RPC handler.
+// - error: an error if the service cannot be registered.
+func NewSigningServer(signer Signer) (http.Handler, error) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &signingService{signer: signer}); err != nil {
+		return nil, errors.Wrap(err, "failed to register signing service")
+	}
+
+	return server, nil
+}
+
+// Accounts returns the account of the signer.
+func (s *signingService) Accounts() []common.Address {
+	return []common.Address{s.signer.Address()}
+}
+
+// Sign signs a message with the Ethereum signed message prefix.
+func (s *signingService) Sign(address common.Address, data hexutil.Bytes) (hexutil.Bytes, error) {
+	if address != s.signer.Address() {
+		return nil, errors.Errorf("unknown account %s", address.Hex())
+	}
+
+	return s.signer.Sign(data)
+}
+
+// SignTypedData signs EIP-712 typed data.
+func (s *signingService) SignTypedData(address common.Address, typedData apitypes.TypedData) (hexutil.Bytes, error) {
+	if address != s.signer.Address() {
+		return nil, errors.Errorf("unknown account %s", address.Hex())
+	}
+
+	return s.signer.SignTypedData(typedData.Domain, typedData.Types, typedData.Message)
+}
+
+// SignTransaction signs a transaction and returns it RLP encoded.
+func (s *signingService) SignTransaction(args transactionArgs) (hexutil.Bytes, error) {
+	if args.From != s.signer.Address() {
+		return nil, errors.Errorf("unknown account %s", args.From.Hex())
+	}
+	if args.ChainID == nil {
+		return nil, errors.New("missing chainId")
+	}
+
+	tx, err := args.toTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	signedTx, err := s.signer.SignTx(tx, args.ChainID.ToInt())
+	if err != nil {
+		return nil, err
+	}
+
+	return signedTx.MarshalBinary()
+}
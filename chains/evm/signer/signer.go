@@ -7,6 +7,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"github.com/pkg/errors"
 	"math/big"
 )
@@ -34,6 +35,20 @@ type Signer interface {
 	// - error: an error if the signing process fails.
 	SignTx(transaction *ethtypes.Transaction, chainID *big.Int) (*ethtypes.Transaction, error)
 
+	// SignTypedData signs EIP-712 typed data and returns the signature.
+	// The domain type is derived from the set domain fields if the types do not define it,
+	// and the primary type is the only type no other type refers to.
+	//
+	// Parameters:
+	// - domain: the signing domain.
+	// - types: the type definitions of the message.
+	// - message: the message.
+	//
+	// Returns:
+	// - []byte: the signature, with V of 27/28.
+	// - error: an error if the typed data is invalid or the signing process fails.
+	SignTypedData(domain apitypes.TypedDataDomain, types apitypes.Types, message apitypes.TypedDataMessage) ([]byte, error)
+
 	// Address returns the signer's address.
 	//
 	// Returns:
@@ -88,6 +103,36 @@ func (s *signer) Sign(data []byte) ([]byte, error) {
 	return signature, nil
 }
 
+// SignTypedData signs EIP-712 typed data and returns the signature.
+//
+// Parameters:
+// - domain: the signing domain.
+// - types: the type definitions of the message.
+// - message: the message.
+//
+// Returns:
+// - []byte: the signature.
+// - error: an error if the typed data is invalid or the signing process fails.
+func (s *signer) SignTypedData(domain apitypes.TypedDataDomain, types apitypes.Types, message apitypes.TypedDataMessage) ([]byte, error) {
+	typedData, err := newTypedData(domain, types, message)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := typedDataHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := crypto.Sign(hash, s.privateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign typed data")
+	}
+	signature[64] += 27 // Transform V from 0/1 to 27/28 according to the yellow paper
+
+	return signature, nil
+}
+
 // Address returns the signer's address.
 //
 // Returns:
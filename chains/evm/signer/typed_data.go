@@ -0,0 +1,173 @@
+package signer
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/pkg/errors"
+	"math/big"
+	"strings"
+)
+
+// eip712DomainType is the name of the EIP-712 domain type.
+const eip712DomainType = "EIP712Domain"
+
+// newTypedData assembles EIP-712 typed data. The domain type is derived from the set domain fields
+// if the types do not define it, and the primary type is the only type no other type refers to.
+//
+// Parameters:
+// - domain: the signing domain.
+// - types: the type definitions of the message.
+// - message: the message.
+//
+// Returns:
+// - apitypes.TypedData: the typed data.
+// - error: an error if the primary type cannot be determined.
+func newTypedData(domain apitypes.TypedDataDomain, types apitypes.Types, message apitypes.TypedDataMessage) (apitypes.TypedData, error) {
+	allTypes := make(apitypes.Types, len(types)+1)
+	for name, fields := range types {
+		allTypes[name] = fields
+	}
+	if _, ok := allTypes[eip712DomainType]; !ok {
+		allTypes[eip712DomainType] = domainType(domain)
+	}
+
+	referenced := make(map[string]bool)
+	for _, fields := range allTypes {
+		for _, field := range fields {
+			referenced[strings.Split(field.Type, "[")[0]] = true
+		}
+	}
+
+	var primaryTypes []string
+	for name := range allTypes {
+		if name != eip712DomainType && !referenced[name] {
+			primaryTypes = append(primaryTypes, name)
+		}
+	}
+	if len(primaryTypes) != 1 {
+		return apitypes.TypedData{}, errors.Errorf("cannot determine primary type among %v", primaryTypes)
+	}
+
+	return apitypes.TypedData{
+		Types:       allTypes,
+		PrimaryType: primaryTypes[0],
+		Domain:      domain,
+		Message:     message,
+	}, nil
+}
+
+// domainType returns the EIP-712 domain type with the set fields of a domain, in the order of the standard.
+func domainType(domain apitypes.TypedDataDomain) []apitypes.Type {
+	var fields []apitypes.Type
+	if domain.Name != "" {
+		fields = append(fields, apitypes.Type{Name: "name", Type: "string"})
+	}
+	if domain.Version != "" {
+		fields = append(fields, apitypes.Type{Name: "version", Type: "string"})
+	}
+	if domain.ChainId != nil {
+		fields = append(fields, apitypes.Type{Name: "chainId", Type: "uint256"})
+	}
+	if domain.VerifyingContract != "" {
+		fields = append(fields, apitypes.Type{Name: "verifyingContract", Type: "address"})
+	}
+	if domain.Salt != "" {
+		fields = append(fields, apitypes.Type{Name: "salt", Type: "bytes32"})
+	}
+	return fields
+}
+
+// typedDataHash returns the EIP-712 hash of typed data, the digest that is signed.
+//
+// Parameters:
+// - typedData: the typed data.
+//
+// Returns:
+// - []byte: the hash.
+// - error: an error if the message does not match its types.
+func typedDataHash(typedData apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to hash typed data")
+	}
+	return hash, nil
+}
+
+// recoverAddress recovers the address that signed a hash from a signature with V of 27/28.
+func recoverAddress(hash []byte, signature []byte) (common.Address, error) {
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, errors.Errorf("invalid signature length %d", len(signature))
+	}
+
+	recoverable := append([]byte(nil), signature...)
+	if recoverable[64] >= 27 {
+		recoverable[64] -= 27
+	}
+
+	publicKey, err := crypto.SigToPub(hash, recoverable)
+	if err != nil {
+		return common.Address{}, errors.Wrap(err, "failed to recover public key")
+	}
+
+	return crypto.PubkeyToAddress(*publicKey), nil
+}
+
+// RecoverTypedDataSigner recovers the address that signed EIP-712 typed data.
+//
+// Parameters:
+// - domain: the signing domain.
+// - types: the type definitions of the message, the domain type is derived if missing.
+// - message: the message.
+// - signature: the signature, with V of 0/1 or 27/28.
+//
+// Returns:
+// - common.Address: the address of the signer.
+// - error: an error if the typed data cannot be hashed or the signature is invalid.
+func RecoverTypedDataSigner(domain apitypes.TypedDataDomain, types apitypes.Types, message apitypes.TypedDataMessage, signature []byte) (common.Address, error) {
+	typedData, err := newTypedData(domain, types, message)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	hash, err := typedDataHash(typedData)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return recoverAddress(hash, signature)
+}
+
+// encodeTypedDataMessage returns a copy of a message whose values survive a JSON round trip:
+// integers become decimal strings and byte slices hex strings.
+func encodeTypedDataMessage(message apitypes.TypedDataMessage) apitypes.TypedDataMessage {
+	return encodeTypedDataValue(message).(apitypes.TypedDataMessage)
+}
+
+// encodeTypedDataValue returns a copy of a message value whose values survive a JSON round trip.
+func encodeTypedDataValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		encoded := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			encoded[key] = encodeTypedDataValue(item)
+		}
+		return encoded
+	case []interface{}:
+		encoded := make([]interface{}, len(v))
+		for i, item := range v {
+			encoded[i] = encodeTypedDataValue(item)
+		}
+		return encoded
+	case *big.Int:
+		return v.String()
+	case *math.HexOrDecimal256:
+		return (*big.Int)(v).String()
+	case []byte:
+		return hexutil.Bytes(v)
+	default:
+		return value
+	}
+}
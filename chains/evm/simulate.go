@@ -0,0 +1,249 @@
+package evm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/ClipFinance/relay-lib/chains/evm/generated"
+	"github.com/ClipFinance/relay-lib/common/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+var (
+	// ErrInsufficientBalance is the cause of a revert or failure due to the sender's balance.
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	// ErrInsufficientAllowance is the cause of a revert due to the spender's allowance.
+	ErrInsufficientAllowance = errors.New("insufficient allowance")
+	// ErrBlockedAddress is the cause of a revert due to a token blocking the sender or the recipient.
+	ErrBlockedAddress = errors.New("blocked address")
+	// ErrTokenPaused is the cause of a revert due to a paused token.
+	ErrTokenPaused = errors.New("token paused")
+)
+
+var (
+	// errorSelector is the selector of Error(string).
+	errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+	// panicSelector is the selector of Panic(uint256).
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+)
+
+// revertReasonCauses are the causes of Error(string) reverts, recognized by a lower case part of the reason.
+var revertReasonCauses = []struct {
+	part  string
+	cause error
+}{
+	{"exceeds balance", ErrInsufficientBalance},
+	{"insufficient balance", ErrInsufficientBalance},
+	{"exceeds allowance", ErrInsufficientAllowance},
+	{"insufficient allowance", ErrInsufficientAllowance},
+	{"blacklisted", ErrBlockedAddress},
+	{"blocklisted", ErrBlockedAddress},
+	{"blocked", ErrBlockedAddress},
+	{"frozen", ErrBlockedAddress},
+	{"paused", ErrTokenPaused},
+}
+
+// erc20ABI is the ERC20 ABI the custom errors of a revert are decoded with.
+var erc20ABI, erc20ABIErr = abi.JSON(strings.NewReader(generated.ERC20ABI))
+
+// customErrorCauses are the causes of the custom errors of the ERC20 ABI.
+var customErrorCauses = map[string]error{
+	"ERC20InsufficientBalance":   ErrInsufficientBalance,
+	"ERC20InsufficientAllowance": ErrInsufficientAllowance,
+	"EnforcedPause":              ErrTokenPaused,
+}
+
+// RevertError is a revert of a simulated transaction, with its decoded reason.
+//
+// Fields:
+// - Reason: the Error(string) message, the Panic(uint256) reason or the custom error with its arguments.
+// - Data: the raw revert data.
+type RevertError struct {
+	Reason string
+	Data   []byte
+	cause  error // Recognized cause of the revert, nil if unknown.
+}
+
+// Error returns the revert reason.
+func (e *RevertError) Error() string {
+	if e.Reason == "" {
+		return "execution reverted"
+	}
+	return "execution reverted: " + e.Reason
+}
+
+// Unwrap returns the recognized cause of the revert, such as ErrInsufficientBalance.
+func (e *RevertError) Unwrap() error {
+	return e.cause
+}
+
+// simulateTransaction executes a prepared transaction with eth_call against the pending block
+// to catch a revert before the transaction is signed.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - client: the client the transaction is sent with.
+// - from: the account sending the transaction.
+// - tx: the prepared transaction.
+//
+// Returns:
+// - error: a *RevertError if the transaction reverts, ErrInsufficientBalance if the sender cannot pay for it,
+// or an error if the call fails.
+func (e *evm) simulateTransaction(ctx context.Context, client *ethclient.Client, from common.Address, tx *ethtypes.Transaction) error {
+	msg := ethereum.CallMsg{
+		From:       from,
+		To:         tx.To(),
+		Gas:        tx.Gas(),
+		Value:      tx.Value(),
+		Data:       tx.Data(),
+		AccessList: tx.AccessList(),
+	}
+	if tx.Type() == ethtypes.DynamicFeeTxType {
+		msg.GasFeeCap = tx.GasFeeCap()
+		msg.GasTipCap = tx.GasTipCap()
+	} else {
+		msg.GasPrice = tx.GasPrice()
+	}
+
+	if _, err := client.PendingCallContract(ctx, msg); err != nil {
+		if decodedErr := decodeCallError(err); decodedErr != err {
+			return decodedErr
+		}
+		return errors.Wrap(err, "failed to simulate transaction")
+	}
+
+	return nil
+}
+
+// decodeCallError turns the error of eth_call or eth_estimateGas into a typed error.
+//
+// Parameters:
+// - err: the error returned by the node.
+//
+// Returns:
+// - error: a *RevertError if the call reverted, ErrInsufficientBalance if the sender cannot pay for it,
+// the unchanged error otherwise.
+func decodeCallError(err error) error {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if encoded, ok := dataErr.ErrorData().(string); ok {
+			if data, decodeErr := hexutil.Decode(encoded); decodeErr == nil && len(data) > 0 {
+				return decodeRevert(data)
+			}
+		}
+	}
+
+	message := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(message, "insufficient funds"):
+		return errors.Wrap(ErrInsufficientBalance, err.Error())
+	case strings.Contains(message, "execution reverted"):
+		revertErr := &RevertError{Reason: strings.TrimPrefix(strings.TrimPrefix(err.Error(), "execution reverted"), ": ")}
+		revertErr.cause = reasonCause(revertErr.Reason)
+		return revertErr
+	default:
+		return err
+	}
+}
+
+// decodeRevert decodes revert data: Error(string), Panic(uint256) or a custom error of the ERC20 ABI.
+//
+// Parameters:
+// - data: the revert data.
+//
+// Returns:
+// - *RevertError: the decoded revert, with the raw data as reason if it cannot be decoded.
+func decodeRevert(data []byte) *RevertError {
+	revertErr := &RevertError{Reason: hexutil.Encode(data), Data: data}
+	if len(data) < 4 {
+		return revertErr
+	}
+
+	switch {
+	case bytes.Equal(data[:4], errorSelector):
+		if reason, err := abi.UnpackRevert(data); err == nil {
+			revertErr.Reason = reason
+			revertErr.cause = reasonCause(reason)
+		}
+		return revertErr
+	case bytes.Equal(data[:4], panicSelector):
+		if reason, err := abi.UnpackRevert(data); err == nil {
+			revertErr.Reason = "panic: " + reason
+		}
+		return revertErr
+	}
+
+	if erc20ABIErr != nil {
+		return revertErr
+	}
+
+	for name, customErr := range erc20ABI.Errors {
+		if !bytes.Equal(customErr.ID[:4], data[:4]) {
+			continue
+		}
+
+		args, err := customErr.Inputs.Unpack(data[4:])
+		if err != nil {
+			revertErr.Reason = name
+		} else {
+			values := make([]string, len(args))
+			for i, arg := range args {
+				values[i] = fmt.Sprint(arg)
+			}
+			revertErr.Reason = fmt.Sprintf("%s(%s)", name, strings.Join(values, ", "))
+		}
+		revertErr.cause = customErrorCauses[name]
+		break
+	}
+
+	return revertErr
+}
+
+// reasonCause returns the recognized cause of an Error(string) revert reason.
+func reasonCause(reason string) error {
+	reason = strings.ToLower(reason)
+	for _, known := range revertReasonCauses {
+		if strings.Contains(reason, known.part) {
+			return known.cause
+		}
+	}
+	return nil
+}
+
+// sendErrorOf attaches the intent sub-status to a SendAsset error caused by a simulated revert
+// or an insufficient balance.
+//
+// Parameters:
+// - err: the error.
+//
+// Returns:
+// - error: a *types.SendError for a recognized cause, the error otherwise.
+func sendErrorOf(err error) error {
+	var subStatus types.SubStatus
+	var revertErr *RevertError
+
+	switch {
+	case errors.Is(err, ErrInsufficientBalance):
+		subStatus = types.InsufficientBalance
+	case errors.Is(err, ErrInsufficientAllowance):
+		subStatus = types.InsufficientAllowance
+	case errors.Is(err, ErrBlockedAddress):
+		subStatus = types.BlockedAddress
+	case errors.Is(err, ErrTokenPaused):
+		subStatus = types.TokenPaused
+	case errors.As(err, &revertErr):
+		subStatus = types.TransactionReverted
+	default:
+		return err
+	}
+
+	return &types.SendError{SubStatus: subStatus, Err: err}
+}
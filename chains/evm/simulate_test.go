@@ -0,0 +1,133 @@
+package evm
+
+import (
+	"github.com/ClipFinance/relay-lib/common/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"math/big"
+	"testing"
+)
+
+// dataError is a JSON-RPC error carrying revert data, like the error of a reverted eth_call.
+type dataError struct {
+	data string
+}
+
+func (e *dataError) Error() string {
+	return "execution reverted"
+}
+
+func (e *dataError) ErrorCode() int {
+	return 3
+}
+
+func (e *dataError) ErrorData() interface{} {
+	return e.data
+}
+
+func TestDecodeCallError(t *testing.T) {
+	stringType, _ := abi.NewType("string", "", nil)
+	uintType, _ := abi.NewType("uint256", "", nil)
+
+	pack := func(selector []byte, arguments abi.Arguments, values ...interface{}) *dataError {
+		t.Helper()
+
+		packed, err := arguments.Pack(values...)
+		if err != nil {
+			t.Fatalf("Pack() error = %v", err)
+		}
+		return &dataError{data: hexutil.Encode(append(append([]byte{}, selector...), packed...))}
+	}
+
+	sender := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	insufficientBalance := erc20ABI.Errors["ERC20InsufficientBalance"]
+	enforcedPause := erc20ABI.Errors["EnforcedPause"]
+
+	tests := []struct {
+		name          string
+		err           error
+		wantReason    string
+		wantCause     error
+		wantSubStatus types.SubStatus
+	}{
+		{
+			name:          "Error(string)",
+			err:           pack(errorSelector, abi.Arguments{{Type: stringType}}, "ERC20: transfer amount exceeds balance"),
+			wantReason:    "ERC20: transfer amount exceeds balance",
+			wantCause:     ErrInsufficientBalance,
+			wantSubStatus: types.InsufficientBalance,
+		},
+		{
+			name:          "Panic(uint256)",
+			err:           pack(panicSelector, abi.Arguments{{Type: uintType}}, big.NewInt(0x11)),
+			wantReason:    "panic: arithmetic underflow or overflow",
+			wantSubStatus: types.TransactionReverted,
+		},
+		{
+			name:          "ERC20InsufficientBalance",
+			err:           pack(insufficientBalance.ID[:4], insufficientBalance.Inputs, sender, big.NewInt(1), big.NewInt(2)),
+			wantReason:    "ERC20InsufficientBalance(" + sender.Hex() + ", 1, 2)",
+			wantCause:     ErrInsufficientBalance,
+			wantSubStatus: types.InsufficientBalance,
+		},
+		{
+			name:          "EnforcedPause",
+			err:           pack(enforcedPause.ID[:4], enforcedPause.Inputs),
+			wantReason:    "EnforcedPause()",
+			wantCause:     ErrTokenPaused,
+			wantSubStatus: types.TokenPaused,
+		},
+		{
+			name:          "unknown selector",
+			err:           &dataError{data: "0xdeadbeef"},
+			wantReason:    "0xdeadbeef",
+			wantSubStatus: types.TransactionReverted,
+		},
+		{
+			name:          "message only",
+			err:           errors.New("execution reverted: Pausable: paused"),
+			wantReason:    "Pausable: paused",
+			wantCause:     ErrTokenPaused,
+			wantSubStatus: types.TokenPaused,
+		},
+		{
+			name:          "message without reason",
+			err:           errors.New("execution reverted"),
+			wantSubStatus: types.TransactionReverted,
+		},
+		{
+			name:          "insufficient funds",
+			err:           errors.New("insufficient funds for gas * price + value"),
+			wantCause:     ErrInsufficientBalance,
+			wantSubStatus: types.InsufficientBalance,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded := decodeCallError(tt.err)
+
+			var revertErr *RevertError
+			if errors.As(decoded, &revertErr) {
+				if revertErr.Reason != tt.wantReason {
+					t.Fatalf("Reason = %q, want %q", revertErr.Reason, tt.wantReason)
+				}
+				if revertErr.Unwrap() != tt.wantCause {
+					t.Fatalf("cause = %v, want %v", revertErr.Unwrap(), tt.wantCause)
+				}
+			} else if tt.wantReason != "" || !errors.Is(decoded, tt.wantCause) {
+				t.Fatalf("decodeCallError() = %v, want a revert with reason %q", decoded, tt.wantReason)
+			}
+
+			var sendErr *types.SendError
+			if !errors.As(sendErrorOf(decoded), &sendErr) {
+				t.Fatalf("sendErrorOf() = %v, want a *types.SendError", sendErrorOf(decoded))
+			}
+			if sendErr.SubStatus != tt.wantSubStatus {
+				t.Fatalf("SubStatus = %s, want %s", sendErr.SubStatus, tt.wantSubStatus)
+			}
+		})
+	}
+}
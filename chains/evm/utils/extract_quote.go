@@ -4,7 +4,6 @@ import (
 	"encoding/hex"
 	"fmt"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/pkg/errors"
 )
 
@@ -14,12 +13,19 @@ const (
 )
 
 // ExtractQuoteIDFromTxData extracts quote ID from transaction input data
+//
+// Deprecated: the quote ID of a Transfer is extracted by the watch list, see WatchList.Decode.
 func ExtractQuoteIDFromTxData(data []byte) (string, error) {
-	if len(data) <= minTransferInputLength {
-		return "", errors.New("invalid transaction input length, expected: " + fmt.Sprint(minTransferInputLength) + ", got: " + fmt.Sprint(len(data)))
+	return extractQuoteID(data, minTransferInputLength)
+}
+
+// extractQuoteID extracts quote ID from transaction input data after the given offset
+func extractQuoteID(data []byte, offset int) (string, error) {
+	if len(data) <= offset {
+		return "", errors.New("invalid transaction input length, expected: " + fmt.Sprint(offset) + ", got: " + fmt.Sprint(len(data)))
 	}
 
-	quoteIDBytes := data[minTransferInputLength:]
+	quoteIDBytes := data[offset:]
 	if len(quoteIDBytes) == 0 {
 		return "", errors.New("quote ID is empty")
 	}
@@ -28,20 +34,14 @@ func ExtractQuoteIDFromTxData(data []byte) (string, error) {
 }
 
 // GetEventType determines event type from log topics
+//
+// Deprecated: the event type is determined by the watch list, see WatchList.EventType.
 func GetEventType(log types.Log) string {
-	if len(log.Topics) == 0 {
+	// The relay receiver events are matched whatever contract emitted them.
+	watchList, err := NewWatchList(DefaultWatchList(log.Address.Hex()))
+	if err != nil {
 		return ""
 	}
 
-	eventSig := log.Topics[0].Hex()
-	switch eventSig {
-	case crypto.Keccak256Hash([]byte("FundsForwarded()")).Hex():
-		return "FundsForwarded"
-	case crypto.Keccak256Hash([]byte("FundsForwardedWithData(bytes)")).Hex():
-		return "FundsForwardedWithData"
-	case crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)")).Hex():
-		return "Transfer"
-	default:
-		return ""
-	}
+	return watchList.EventType(log)
 }
@@ -3,11 +3,15 @@ package utils
 const (
 	// ZeroAddress represents the zero address.
 	ZeroAddress = "0x0000000000000000000000000000000000000000"
+	// EventTypeNativeTransfer is the event type of a native token transfer to the solver.
+	EventTypeNativeTransfer = "NativeTransfer"
 )
 
 // EvmMetadata represents the metadata for an EVM event.
+// Native transfers have no log, they are identified by the TraceAddress of their call within the transaction.
 type EvmMetadata struct {
-	EventType string
-	LogIndex  uint
-	Data      []byte
+	EventType    string
+	LogIndex     uint
+	Data         []byte
+	TraceAddress string
 }
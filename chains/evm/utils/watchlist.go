@@ -0,0 +1,510 @@
+package utils
+
+import (
+	"encoding/hex"
+	"github.com/ClipFinance/relay-lib/chains/evm/generated"
+	relaytypes "github.com/ClipFinance/relay-lib/common/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"math/big"
+	"strings"
+)
+
+// DecodedEvent represents a deposit decoded from the log of a watched event.
+//
+// Fields:
+// - EventType: the event type of the watched event.
+// - QuoteID: the quote ID the deposit is made for.
+// - Amount: the deposited amount in base units.
+// - Depositor: the depositor address read from the event argument or the caller of the emitting call,
+// empty if neither applies.
+type DecodedEvent struct {
+	EventType string
+	QuoteID   string
+	Amount    string
+	Depositor string
+}
+
+// Call is the call that emitted a log, which may be an inner call of the transaction.
+//
+// Fields:
+// - From: the account making the call.
+// - Value: the native value sent with the call.
+type Call struct {
+	From  common.Address
+	Value *big.Int
+}
+
+// watchedEvent is a watched event with its ABI parsed.
+type watchedEvent struct {
+	config    relaytypes.WatchedEvent // Declared event.
+	eventType string                  // Event type reported in the metadata.
+	event     abi.Event               // Parsed ABI event.
+	contracts map[common.Address]bool // Emitting contracts, any contract if empty.
+}
+
+// WatchList decodes the logs of the declared events into deposits and builds the filter queries matching them.
+// Events emitted by declared contracts are delivered by the relay query, events addressed to the solver
+// by the transfer query.
+type WatchList struct {
+	events         map[common.Hash][]*watchedEvent // Watched events by event signature.
+	relayEvents    []*watchedEvent                 // Events matched by contract address.
+	transferEvents []*watchedEvent                 // Events matched by the solver topic.
+}
+
+// DefaultWatchList returns the relay receiver FundsForwarded and FundsForwardedWithData events
+// and the ERC20 transfers to the solver. FundsForwardedWithData deposits take their depositor and amount
+// from the call emitting the event, so deposits made through smart wallets, multicalls or bundlers are reported
+// with their own sender and value.
+//
+// Parameters:
+// - relayReceiver: the address of the relay receiver, its events are not watched if empty.
+//
+// Returns:
+// - []relaytypes.WatchedEvent: the default watched events.
+func DefaultWatchList(relayReceiver string) []relaytypes.WatchedEvent {
+	var events []relaytypes.WatchedEvent
+
+	if relayReceiver != "" {
+		events = append(events,
+			relaytypes.WatchedEvent{
+				ABI:       generated.RelayReceiverABI,
+				Event:     "FundsForwarded",
+				Contracts: []string{relayReceiver},
+				QuoteID:   relaytypes.QuoteIDRule{Source: relaytypes.QuoteIDFromCalldata},
+				Amount:    relaytypes.AmountRule{Source: relaytypes.AmountFromTxValue},
+			},
+			relaytypes.WatchedEvent{
+				ABI:       generated.RelayReceiverABI,
+				Event:     "FundsForwardedWithData",
+				Contracts: []string{relayReceiver},
+				QuoteID:   relaytypes.QuoteIDRule{Source: relaytypes.QuoteIDFromEventArg, Arg: "data"},
+				Amount:    relaytypes.AmountRule{Source: relaytypes.AmountFromCallValue},
+			},
+		)
+	}
+
+	return append(events, relaytypes.WatchedEvent{
+		ABI:          generated.ERC20ABI,
+		Event:        "Transfer",
+		SolverTopic:  2,
+		QuoteID:      relaytypes.QuoteIDRule{Source: relaytypes.QuoteIDFromCalldataSuffix, Offset: minTransferInputLength},
+		Amount:       relaytypes.AmountRule{Source: relaytypes.AmountFromEventArg, Arg: "value"},
+		DepositorArg: "from",
+	})
+}
+
+// NewWatchList parses the ABIs of the declared events and validates their extraction rules.
+//
+// Parameters:
+// - events: the declared events.
+//
+// Returns:
+// - *WatchList: a new WatchList instance.
+// - error: an error if an event is invalid.
+func NewWatchList(events []relaytypes.WatchedEvent) (*WatchList, error) {
+	if len(events) == 0 {
+		return nil, errors.New("watch list is empty")
+	}
+
+	w := &WatchList{events: make(map[common.Hash][]*watchedEvent)}
+
+	for _, config := range events {
+		event, err := newWatchedEvent(config)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid watched event %s", config.Event)
+		}
+
+		if config.SolverTopic > 0 {
+			if len(w.transferEvents) > 0 && w.transferEvents[0].config.SolverTopic != config.SolverTopic {
+				return nil, errors.Errorf("watched event %s has solver topic %d, other events use %d", event.eventType, config.SolverTopic, w.transferEvents[0].config.SolverTopic)
+			}
+			w.transferEvents = append(w.transferEvents, event)
+		} else {
+			w.relayEvents = append(w.relayEvents, event)
+		}
+
+		w.events[event.event.ID] = append(w.events[event.event.ID], event)
+	}
+
+	return w, nil
+}
+
+// newWatchedEvent parses the ABI of a declared event and validates its extraction rules.
+func newWatchedEvent(config relaytypes.WatchedEvent) (*watchedEvent, error) {
+	parsed, err := abi.JSON(strings.NewReader(config.ABI))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse ABI")
+	}
+
+	event, ok := parsed.Events[config.Event]
+	if !ok {
+		return nil, errors.New("event not found in ABI")
+	}
+	if event.Anonymous {
+		return nil, errors.New("anonymous events are not supported")
+	}
+
+	contracts := make(map[common.Address]bool, len(config.Contracts))
+	for _, contract := range config.Contracts {
+		if !common.IsHexAddress(contract) {
+			return nil, errors.Errorf("invalid contract address %q", contract)
+		}
+		contracts[common.HexToAddress(contract)] = true
+	}
+
+	indexed := indexedInputs(event)
+
+	if config.SolverTopic < 0 || config.SolverTopic > len(indexed) {
+		return nil, errors.Errorf("solver topic %d out of range", config.SolverTopic)
+	}
+	if config.SolverTopic > 0 && indexed[config.SolverTopic-1].Type.T != abi.AddressTy {
+		return nil, errors.Errorf("solver topic %d is not an address", config.SolverTopic)
+	}
+	if config.SolverTopic == 0 && len(contracts) == 0 {
+		return nil, errors.New("event needs contracts or a solver topic")
+	}
+
+	switch config.QuoteID.Source {
+	case relaytypes.QuoteIDFromEventArg:
+		if !hasInput(event, config.QuoteID.Arg) {
+			return nil, errors.Errorf("quote ID argument %q not found", config.QuoteID.Arg)
+		}
+	case relaytypes.QuoteIDFromCalldataSuffix:
+		if config.QuoteID.Offset < 0 {
+			return nil, errors.Errorf("invalid quote ID calldata offset %d", config.QuoteID.Offset)
+		}
+	case relaytypes.QuoteIDFromCalldata:
+	case relaytypes.QuoteIDFromTopic:
+		if config.QuoteID.Topic < 1 || config.QuoteID.Topic > len(indexed) {
+			return nil, errors.Errorf("quote ID topic %d out of range", config.QuoteID.Topic)
+		}
+	default:
+		return nil, errors.Errorf("unknown quote ID source %q", config.QuoteID.Source)
+	}
+
+	switch config.Amount.Source {
+	case relaytypes.AmountFromTxValue, relaytypes.AmountFromCallValue:
+	case relaytypes.AmountFromEventArg:
+		if !hasInput(event, config.Amount.Arg) {
+			return nil, errors.Errorf("amount argument %q not found", config.Amount.Arg)
+		}
+	default:
+		return nil, errors.Errorf("unknown amount source %q", config.Amount.Source)
+	}
+
+	if config.DepositorArg != "" {
+		input, ok := findInput(event, config.DepositorArg)
+		if !ok {
+			return nil, errors.Errorf("depositor argument %q not found", config.DepositorArg)
+		}
+		if input.Type.T != abi.AddressTy {
+			return nil, errors.Errorf("depositor argument %q is not an address", config.DepositorArg)
+		}
+	}
+
+	eventType := config.EventType
+	if eventType == "" {
+		eventType = event.Name
+	}
+
+	return &watchedEvent{
+		config:    config,
+		eventType: eventType,
+		event:     event,
+		contracts: contracts,
+	}, nil
+}
+
+// RelayQuery returns the filter query matching the events of the declared contracts.
+//
+// Returns:
+// - ethereum.FilterQuery: the filter query.
+// - bool: false if no such event is watched.
+func (w *WatchList) RelayQuery() (ethereum.FilterQuery, bool) {
+	if len(w.relayEvents) == 0 {
+		return ethereum.FilterQuery{}, false
+	}
+
+	addresses, topics := queryFilter(w.relayEvents)
+
+	return ethereum.FilterQuery{
+		Addresses: addresses,
+		Topics:    [][]common.Hash{topics},
+	}, true
+}
+
+// TransferQuery returns the filter query matching the events addressed to the solver.
+//
+// Parameters:
+// - solverAddress: the solver address.
+//
+// Returns:
+// - ethereum.FilterQuery: the filter query.
+// - bool: false if no such event is watched.
+func (w *WatchList) TransferQuery(solverAddress string) (ethereum.FilterQuery, bool) {
+	if len(w.transferEvents) == 0 {
+		return ethereum.FilterQuery{}, false
+	}
+
+	addresses, topics := queryFilter(w.transferEvents)
+
+	solverTopic := w.transferEvents[0].config.SolverTopic
+	filter := make([][]common.Hash, solverTopic+1)
+	filter[0] = topics
+	filter[solverTopic] = []common.Hash{common.BytesToHash(common.HexToAddress(solverAddress).Bytes())}
+
+	return ethereum.FilterQuery{
+		Addresses: addresses,
+		Topics:    filter,
+	}, true
+}
+
+// WatchesContract reports whether events of a contract are watched.
+//
+// Parameters:
+// - address: the contract address.
+//
+// Returns:
+// - bool: true if a watched event is declared for the contract.
+func (w *WatchList) WatchesContract(address common.Address) bool {
+	for _, events := range w.events {
+		for _, event := range events {
+			if event.contracts[address] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// EventType returns the event type of a log.
+//
+// Parameters:
+// - log: the event log.
+//
+// Returns:
+// - string: the event type, or an empty string if the log is not a watched event.
+func (w *WatchList) EventType(log types.Log) string {
+	event := w.match(log)
+	if event == nil {
+		return ""
+	}
+	return event.eventType
+}
+
+// NeedsCall reports whether decoding a log needs the call that emitted it.
+//
+// Parameters:
+// - log: the event log.
+//
+// Returns:
+// - bool: true if the log is a watched event reading its amount from the emitting call.
+func (w *WatchList) NeedsCall(log types.Log) bool {
+	event := w.match(log)
+	return event != nil && event.config.Amount.Source == relaytypes.AmountFromCallValue
+}
+
+// Decode extracts the quote ID, the amount and the depositor of a watched event.
+// The event is decoded from its own log and the call that emitted it, so each of several watched events
+// emitted by one transaction, like deposits batched through a smart wallet or a bundler, is decoded independently.
+//
+// Parameters:
+// - log: the event log.
+// - tx: the transaction that emitted the log.
+// - call: the call that emitted the log, required if NeedsCall reports true.
+//
+// Returns:
+// - *DecodedEvent: the decoded deposit.
+// - error: an error if the log is not a watched event or cannot be decoded.
+func (w *WatchList) Decode(log types.Log, tx *types.Transaction, call *Call) (*DecodedEvent, error) {
+	event := w.match(log)
+	if event == nil {
+		return nil, errors.New("log is not a watched event")
+	}
+
+	args := make(map[string]interface{})
+	if err := event.event.Inputs.NonIndexed().UnpackIntoMap(args, log.Data); err != nil {
+		return nil, errors.Wrapf(err, "failed to unpack %s event data", event.eventType)
+	}
+	if err := abi.ParseTopicsIntoMap(args, indexedInputs(event.event), log.Topics[1:]); err != nil {
+		return nil, errors.Wrapf(err, "failed to unpack %s event topics", event.eventType)
+	}
+
+	quoteID, err := event.quoteID(log, tx, args)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to extract quoteId from %s event", event.eventType)
+	}
+
+	amount, err := event.amount(tx, call, args)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to extract amount from %s event", event.eventType)
+	}
+
+	decoded := &DecodedEvent{
+		EventType: event.eventType,
+		QuoteID:   quoteID,
+		Amount:    amount,
+	}
+
+	if event.config.DepositorArg != "" {
+		depositor, ok := args[event.config.DepositorArg].(common.Address)
+		if !ok {
+			return nil, errors.Errorf("failed to extract depositor from %s event", event.eventType)
+		}
+		decoded.Depositor = depositor.Hex()
+	} else if event.config.Amount.Source == relaytypes.AmountFromCallValue {
+		decoded.Depositor = call.From.Hex()
+	}
+
+	return decoded, nil
+}
+
+// match returns the watched event a log belongs to.
+func (w *WatchList) match(log types.Log) *watchedEvent {
+	if len(log.Topics) == 0 {
+		return nil
+	}
+
+	for _, event := range w.events[log.Topics[0]] {
+		if len(event.contracts) > 0 && !event.contracts[log.Address] {
+			continue
+		}
+		// Events sharing a signature, like ERC20 and ERC721 transfers, differ in their indexed arguments.
+		if len(log.Topics) != len(indexedInputs(event.event))+1 {
+			continue
+		}
+		return event
+	}
+
+	return nil
+}
+
+// quoteID extracts the quote ID according to the quote ID rule.
+func (e *watchedEvent) quoteID(log types.Log, tx *types.Transaction, args map[string]interface{}) (string, error) {
+	rule := e.config.QuoteID
+
+	switch rule.Source {
+	case relaytypes.QuoteIDFromEventArg:
+		return formatArg(args[rule.Arg])
+	case relaytypes.QuoteIDFromCalldataSuffix:
+		quoteID, err := extractQuoteID(tx.Data(), rule.Offset)
+		if err != nil {
+			return "", err
+		}
+		return "0x" + quoteID, nil
+	case relaytypes.QuoteIDFromCalldata:
+		return string(tx.Data()), nil
+	case relaytypes.QuoteIDFromTopic:
+		return log.Topics[rule.Topic].Hex(), nil
+	default:
+		return "", errors.Errorf("unknown quote ID source %q", rule.Source)
+	}
+}
+
+// amount extracts the deposited amount according to the amount rule.
+func (e *watchedEvent) amount(tx *types.Transaction, call *Call, args map[string]interface{}) (string, error) {
+	rule := e.config.Amount
+
+	switch rule.Source {
+	case relaytypes.AmountFromTxValue:
+		return tx.Value().String(), nil
+	case relaytypes.AmountFromCallValue:
+		if call == nil {
+			return "", errors.New("call of the event unknown")
+		}
+		return call.Value.String(), nil
+	case relaytypes.AmountFromEventArg:
+		value, ok := args[rule.Arg].(*big.Int)
+		if !ok {
+			return "", errors.Errorf("argument %q is not an integer", rule.Arg)
+		}
+		return value.String(), nil
+	default:
+		return "", errors.Errorf("unknown amount source %q", rule.Source)
+	}
+}
+
+// queryFilter returns the contract addresses and event signatures matching the events.
+// The addresses are nil if any of the events may be emitted by any contract.
+func queryFilter(events []*watchedEvent) ([]common.Address, []common.Hash) {
+	var addresses []common.Address
+	var topics []common.Hash
+	seenAddresses := make(map[common.Address]bool)
+	seenTopics := make(map[common.Hash]bool)
+	anyContract := false
+
+	for _, event := range events {
+		if !seenTopics[event.event.ID] {
+			seenTopics[event.event.ID] = true
+			topics = append(topics, event.event.ID)
+		}
+
+		if len(event.contracts) == 0 {
+			anyContract = true
+			continue
+		}
+		for _, contract := range event.config.Contracts {
+			address := common.HexToAddress(contract)
+			if !seenAddresses[address] {
+				seenAddresses[address] = true
+				addresses = append(addresses, address)
+			}
+		}
+	}
+
+	if anyContract {
+		return nil, topics
+	}
+	return addresses, topics
+}
+
+// indexedInputs returns the indexed arguments of an event.
+func indexedInputs(event abi.Event) abi.Arguments {
+	var indexed abi.Arguments
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	return indexed
+}
+
+// hasInput reports whether an event has an argument with the given name.
+func hasInput(event abi.Event, name string) bool {
+	_, ok := findInput(event, name)
+	return ok
+}
+
+// findInput returns the argument of an event with the given name.
+func findInput(event abi.Event, name string) (abi.Argument, bool) {
+	for _, input := range event.Inputs {
+		if input.Name == name {
+			return input, true
+		}
+	}
+	return abi.Argument{}, false
+}
+
+// formatArg formats a decoded event argument as a quote ID.
+func formatArg(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return "0x" + hex.EncodeToString(v), nil
+	case [32]byte:
+		return "0x" + hex.EncodeToString(v[:]), nil
+	case common.Hash:
+		return v.Hex(), nil
+	case common.Address:
+		return v.Hex(), nil
+	case *big.Int:
+		return v.String(), nil
+	default:
+		return "", errors.Errorf("unsupported quote ID argument type %T", value)
+	}
+}
@@ -51,31 +51,29 @@ func (e *evm) validateTransaction(quote *types.Quote, tx *ethtypes.Transaction,
 	// Validate transaction type (native token transfer or ERC20 token transfer)
 	if quote.Parameters.FromToken == utils.ZeroAddress {
 		// Validate native token transfer transaction
-		return e.validateNativeTransfer(quote, tx)
+		return e.validateNativeTransfer(quote, tx, event)
 	}
 
 	// Validate ERC20 token transfer transaction
 	return e.validateTokenTransfer(quote, event, solverAddr)
 }
 
-// validateNativeTransfer validates native token transfer transaction details
-func (e *evm) validateNativeTransfer(quote *types.Quote, tx *ethtypes.Transaction) error {
+// validateNativeTransfer validates native token transfer details reported by the event,
+// which may be one of several deposits made by the transaction
+func (e *evm) validateNativeTransfer(quote *types.Quote, tx *ethtypes.Transaction, event types.ChainEvent) error {
 	// Validate exact amount match
 	amount := new(big.Int)
 	amount.SetString(quote.Parameters.Amount, 10)
-	if tx.Value().Cmp(amount) != 0 {
-		return errors.New("amount mismatch")
+	transferAmount, success := new(big.Int).SetString(event.TransactionAmount, 10)
+	if !success {
+		return errors.New("failed to parse transfer amount")
 	}
-
-	// Get transaction sender address
-	signer := ethtypes.LatestSignerForChainID(big.NewInt(int64(quote.Parameters.FromChain)))
-	sender, err := ethtypes.Sender(signer, tx)
-	if err != nil {
-		return errors.Wrap(err, "failed to get transaction sender")
+	if transferAmount.Cmp(amount) != 0 {
+		return errors.New("amount mismatch")
 	}
 
 	// Validate sender address match
-	if sender.Hex() != quote.Parameters.UserAddress {
+	if event.FromAddress != common.HexToAddress(quote.Parameters.UserAddress).Hex() {
 		return errors.New("sender address mismatch")
 	}
 
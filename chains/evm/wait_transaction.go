@@ -217,13 +217,13 @@ func (e *evm) handleStuckTransaction(ctx context.Context, tx *types.Transaction)
 		return types.TxFailed, errors.New("failed to cancel stuck transaction")
 	}
 
-	// Update transaction details with new transaction
+	// Update transaction details with new transaction, sent from the same wallet
 	tx.Hash = newTx.Hash().Hex()
-	tx.From = e.signer.Address().Hex()
 	return types.TxNeedsRetry, nil
 }
 
-// replaceTransaction replaces a pending transaction with a new one with a higher gas price.
+// replaceTransaction replaces a pending transaction with a new one with a higher gas price,
+// signed by the wallet that sent it.
 //
 // Parameters:
 // - ctx: the context for managing the request.
@@ -241,6 +241,11 @@ func (e *evm) replaceTransaction(ctx context.Context, tx *types.Transaction) (*e
 		return nil, errors.New("client not initialized")
 	}
 
+	w, err := e.walletFor(tx.From)
+	if err != nil {
+		return nil, err
+	}
+
 	txHash := common.HexToHash(tx.Hash)
 
 	oldTx, isPending, err := client.TransactionByHash(ctx, txHash)
@@ -298,10 +303,11 @@ func (e *evm) replaceTransaction(ctx context.Context, tx *types.Transaction) (*e
 		)
 	}
 
-	return e.signAndSendTransaction(ctx, newTx)
+	return e.signAndSendTransaction(ctx, w.signer, newTx)
 }
 
-// cancelTransaction cancels a pending transaction by sending a new transaction with the same nonce and higher gas price.
+// cancelTransaction cancels a pending transaction by sending a new transaction with the same nonce and higher gas price
+// from the wallet that sent it.
 //
 // Parameters:
 // - ctx: the context for managing the request.
@@ -319,6 +325,11 @@ func (e *evm) cancelTransaction(ctx context.Context, tx *types.Transaction) (*et
 		return nil, errors.New("client not initialized")
 	}
 
+	w, err := e.walletFor(tx.From)
+	if err != nil {
+		return nil, err
+	}
+
 	txHash := common.HexToHash(tx.Hash)
 	transaction, pending, err := client.TransactionByHash(ctx, txHash)
 	if err != nil {
@@ -338,9 +349,7 @@ func (e *evm) cancelTransaction(ctx context.Context, tx *types.Transaction) (*et
 
 	chainID := new(big.Int).SetUint64(e.config.ChainID)
 
-	e.signerMutex.RLock()
-	toAddress := e.signer.Address()
-	e.signerMutex.RUnlock()
+	toAddress := w.signer.Address()
 
 	var newTx *ethtypes.Transaction
 
@@ -366,7 +375,7 @@ func (e *evm) cancelTransaction(ctx context.Context, tx *types.Transaction) (*et
 		)
 	}
 
-	return e.signAndSendTransaction(ctx, newTx)
+	return e.signAndSendTransaction(ctx, w.signer, newTx)
 }
 
 // getNewGasPrice calculates optimal gas price for replacement transaction
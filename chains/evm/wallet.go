@@ -0,0 +1,212 @@
+package evm
+
+import (
+	"context"
+	"github.com/ClipFinance/relay-lib/chains/evm/signer"
+	"github.com/ClipFinance/relay-lib/common/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"math/big"
+	"sort"
+)
+
+// wallet is a solver account transactions are sent from, with its own nonce stream.
+type wallet struct {
+	signer       signer.Signer // Signer of the account.
+	nonceManager *nonceManager // Nonce manager of the account.
+}
+
+// newChainWallets creates the solver wallets of a chain: the wallet of the chain's key, if any, followed by
+// the additional wallets of the configuration.
+//
+// Parameters:
+// - config: the chain configuration.
+//
+// Returns:
+// - []*wallet: the wallets, empty if the chain has no signing key.
+// - error: an error if a wallet cannot be created or two wallets share an account.
+func newChainWallets(config *types.ChainConfig) ([]*wallet, error) {
+	walletConfigs := append([]types.WalletConfig{{
+		PrivateKey:   config.PrivateKey,
+		RemoteSigner: config.RemoteSigner,
+		Keystore:     config.Keystore,
+		Mnemonic:     config.Mnemonic,
+	}}, config.Wallets...)
+
+	var wallets []*wallet
+	accounts := make(map[common.Address]struct{})
+
+	for i := range walletConfigs {
+		walletSigner, err := newWalletSigner(&walletConfigs[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create wallet %d", i)
+		}
+		if walletSigner == nil {
+			if i == 0 {
+				continue
+			}
+			return nil, errors.Errorf("wallet %d has no key", i)
+		}
+
+		address := walletSigner.Address()
+		if _, ok := accounts[address]; ok {
+			return nil, errors.Errorf("wallet %s is configured twice", address.Hex())
+		}
+		accounts[address] = struct{}{}
+
+		wallets = append(wallets, &wallet{
+			signer:       walletSigner,
+			nonceManager: newNonceManager(address),
+		})
+	}
+
+	return wallets, nil
+}
+
+// newWalletSigner creates the signer of a wallet from its key source: a remote signing service,
+// an encrypted keystore, an HD wallet mnemonic or a raw private key. At most one source may be configured.
+//
+// Parameters:
+// - config: the wallet configuration.
+//
+// Returns:
+// - signer.Signer: the signer, or nil if no key source is configured.
+// - error: an error if several key sources are configured or the signer cannot be created.
+func newWalletSigner(config *types.WalletConfig) (signer.Signer, error) {
+	sources := 0
+	for _, configured := range []bool{
+		config.RemoteSigner != nil,
+		config.Keystore != nil,
+		config.Mnemonic != nil,
+		config.PrivateKey != "",
+	} {
+		if configured {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return nil, errors.New("only one of remote signer, keystore, mnemonic and private key can be configured")
+	}
+
+	switch {
+	case config.RemoteSigner != nil:
+		remoteSigner, err := signer.NewRemoteSigner(config.RemoteSigner)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create remote signer")
+		}
+		return remoteSigner, nil
+	case config.Keystore != nil:
+		keystoreSigner, err := signer.NewKeystoreSigner(config.Keystore)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create keystore signer")
+		}
+		return keystoreSigner, nil
+	case config.Mnemonic != nil:
+		mnemonicSigner, err := signer.NewMnemonicSigner(config.Mnemonic)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create mnemonic signer")
+		}
+		return mnemonicSigner, nil
+	case config.PrivateKey == "":
+		return nil, nil
+	}
+
+	privKey, err := crypto.HexToECDSA(config.PrivateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse private key")
+	}
+
+	localSigner, err := signer.NewSigner(privKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create signer")
+	}
+
+	return localSigner, nil
+}
+
+// selectWallets selects the wallets able to send a transfer: the wallets holding at least the transferred amount
+// of the token, ordered by the fewest transactions in flight. The balance does not cover the gas of the transfer,
+// so the caller moves on to the next wallet if the transfer fails with ErrInsufficientBalance.
+// A single wallet is used without checks.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - client: the client used to query balances and nonces.
+// - token: the address of the transferred token, ZeroAddress for the native asset.
+// - amount: the transferred amount.
+//
+// Returns:
+// - []*wallet: the selected wallets, in the order to try them.
+// - error: an error if no wallet is configured, ErrInsufficientBalance if none holds enough of the token.
+func (e *evm) selectWallets(ctx context.Context, client nonceClient, token string, amount *big.Int) ([]*wallet, error) {
+	switch len(e.wallets) {
+	case 0:
+		return nil, errors.New("no wallet configured")
+	case 1:
+		return e.wallets, nil
+	}
+
+	var selected []*wallet
+	inFlights := make(map[*wallet]int)
+
+	for _, w := range e.wallets {
+		address := w.signer.Address()
+		logger := e.logger.WithFields(logrus.Fields{
+			"chain":  e.config.Name,
+			"wallet": address.Hex(),
+		})
+
+		balance, err := e.GetTokenBalance(ctx, address.Hex(), token)
+		if err != nil {
+			logger.WithError(err).Warn("Failed to get wallet balance")
+			continue
+		}
+		if amount != nil && balance.Cmp(amount) < 0 {
+			continue
+		}
+
+		inFlight, err := w.nonceManager.inFlight(ctx, client)
+		if err != nil {
+			logger.WithError(err).Warn("Failed to get wallet transactions in flight")
+			continue
+		}
+
+		selected = append(selected, w)
+		inFlights[w] = inFlight
+	}
+
+	if len(selected) == 0 {
+		return nil, errors.Wrapf(ErrInsufficientBalance, "no wallet holds %s of token %s", amount, token)
+	}
+
+	sort.SliceStable(selected, func(i, j int) bool {
+		return inFlights[selected[i]] < inFlights[selected[j]]
+	})
+
+	return selected, nil
+}
+
+// walletFor returns the wallet of an account, the wallet of the signer if the account is empty.
+//
+// Parameters:
+// - from: the address of the account.
+//
+// Returns:
+// - *wallet: the wallet of the account.
+// - error: an error if no wallet belongs to the account.
+func (e *evm) walletFor(from string) (*wallet, error) {
+	if from == "" && len(e.wallets) > 0 {
+		return e.wallets[0], nil
+	}
+
+	address := common.HexToAddress(from)
+	for _, w := range e.wallets {
+		if w.signer.Address() == address {
+			return w, nil
+		}
+	}
+
+	return nil, errors.Errorf("no wallet for account %s", from)
+}
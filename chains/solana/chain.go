@@ -3,6 +3,9 @@ package solana
 import (
 	"context"
 	"github.com/ClipFinance/relay-lib/chainmanager"
+	"github.com/ClipFinance/relay-lib/chains/solana/handler"
+	"github.com/ClipFinance/relay-lib/chains/solana/signer"
+	"github.com/ClipFinance/relay-lib/chains/solana/solclient"
 	"github.com/ClipFinance/relay-lib/common/types"
 	"github.com/ClipFinance/relay-lib/connectionmonitor"
 	"github.com/pkg/errors"
@@ -17,13 +20,13 @@ type solana struct {
 
 	// Protected fields with their own mutexes
 	clientMutex sync.RWMutex
-	client      interface{} // Replace with actual Solana client type
+	client      *solclient.Client
 
 	signerMutex sync.RWMutex
-	signer      interface{} // Replace with actual Solana signer type
+	signer      signer.Signer
 
 	eventHandlerMutex sync.RWMutex
-	eventHandler      interface{} // Replace with actual Solana event handler type
+	eventHandler      *handler.EventHandler
 
 	monitorMutex sync.RWMutex
 	monitor      connectionmonitor.ConnectionMonitor
@@ -37,7 +40,6 @@ type solana struct {
 func NewSolanaChain(config *types.ChainConfig, logger *logrus.Logger) (types.Chain, error) {
 	ctx := context.Background()
 
-	// Initialize Solana client (replace with actual client initialization)
 	client, err := initSolanaClient(config.RpcUrl)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create client")
@@ -61,7 +63,12 @@ func NewSolanaChain(config *types.ChainConfig, logger *logrus.Logger) (types.Cha
 	builder.WithGasEstimator(chain)
 
 	if config.PrivateKey != "" {
-		signer, err := initSolanaSigner(config.PrivateKey)
+		privKey, err := signer.ParsePrivateKey(config.PrivateKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse private key")
+		}
+
+		signer, err := signer.NewSigner(privKey)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to create signer")
 		}
@@ -71,7 +78,7 @@ func NewSolanaChain(config *types.ChainConfig, logger *logrus.Logger) (types.Cha
 		chain.signerMutex.Unlock()
 
 		chain.solverAddressMutex.Lock()
-		chain.solverAddress = getSolanaAddress(signer) // Replace with actual method
+		chain.solverAddress = signer.Address().String()
 		chain.solverAddressMutex.Unlock()
 
 		builder.WithTransactionSender(chain)
@@ -94,31 +101,28 @@ func (s *solana) Close() {
 
 	s.clientMutex.Lock()
 	if s.client != nil {
-		// Add cleanup for Solana client if needed
+		s.client.Close()
 		s.client = nil
 	}
 	s.clientMutex.Unlock()
 
 	s.eventHandlerMutex.Lock()
 	if s.eventHandler != nil {
-		// Add cleanup for event handler if needed
+		s.eventHandler.Stop()
 		s.eventHandler = nil
 	}
 	s.eventHandlerMutex.Unlock()
 }
 
-// Helper functions (to be implemented by the developer if needed)
-func initSolanaClient(rpcURL string) (interface{}, error) {
-	// TODO: Implement Solana client initialization
-	return nil, nil
-}
-
-func initSolanaSigner(privateKey string) (interface{}, error) {
-	// TODO: Implement Solana signer initialization
-	return nil, nil
+// GetClient returns the Solana client.
+func (s *solana) GetClient() *solclient.Client {
+	s.clientMutex.RLock()
+	defer s.clientMutex.RUnlock()
+	return s.client
 }
 
-func getSolanaAddress(signer interface{}) string {
-	// TODO: Implement getting Solana address from signer
-	return ""
+// initSolanaClient dials the Solana JSON-RPC endpoint.
+// WebSocket URLs are accepted and converted to the matching HTTP endpoint.
+func initSolanaClient(rpcURL string) (*solclient.Client, error) {
+	return solclient.Dial(rpcURL)
 }
@@ -3,6 +3,7 @@ package solana
 import (
 	"context"
 	"errors"
+	"github.com/ClipFinance/relay-lib/chains/solana/solclient"
 	"github.com/ClipFinance/relay-lib/connectionmonitor"
 )
 
@@ -11,6 +12,7 @@ type solanaConnectionManager struct {
 	chain *solana
 }
 
+// CheckConnection checks the node health with getHealth and makes sure the node serves slots.
 func (m *solanaConnectionManager) CheckConnection(ctx context.Context) error {
 	m.chain.clientMutex.RLock()
 	client := m.chain.client
@@ -20,16 +22,21 @@ func (m *solanaConnectionManager) CheckConnection(ctx context.Context) error {
 		return errors.New("client not initialized")
 	}
 
-	// TODO: Implement actual connection check
-	return nil
+	if err := client.GetHealth(ctx); err != nil {
+		return err
+	}
+
+	_, err := client.GetSlot(ctx, solclient.CommitmentProcessed)
+	return err
 }
 
+// Reconnect re-dials the Solana node and replaces the client.
 func (m *solanaConnectionManager) Reconnect(ctx context.Context) error {
 	m.chain.clientMutex.Lock()
 	defer m.chain.clientMutex.Unlock()
 
 	if m.chain.client != nil {
-		// TODO: Cleanup old client if needed
+		m.chain.client.Close()
 	}
 
 	client, err := initSolanaClient(m.chain.config.RpcUrl)
@@ -41,7 +48,7 @@ func (m *solanaConnectionManager) Reconnect(ctx context.Context) error {
 
 	m.chain.eventHandlerMutex.Lock()
 	if m.chain.eventHandler != nil {
-		// TODO: Update client in event handler if needed
+		m.chain.eventHandler.UpdateClient(client)
 	}
 	m.chain.eventHandlerMutex.Unlock()
 
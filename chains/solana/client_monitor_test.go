@@ -0,0 +1,80 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ClipFinance/relay-lib/chains/solana/solclient"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newStandInClient starts an httptest server answering getHealth and getSlot like a Solana node.
+func newStandInClient(t *testing.T, health interface{}, slotErr bool) *solclient.Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		response := map[string]interface{}{"jsonrpc": "2.0", "id": request.ID}
+		switch {
+		case request.Method == "getHealth":
+			response["result"] = health
+		case request.Method == "getSlot" && !slotErr:
+			response["result"] = 1234
+		default:
+			response["error"] = map[string]interface{}{"code": -32603, "message": "Internal error"}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := solclient.Dial(server.URL)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	return client
+}
+
+func TestCheckConnection(t *testing.T) {
+	tests := []struct {
+		name    string
+		health  interface{}
+		slotErr bool
+		wantErr bool
+	}{
+		{name: "healthy", health: "ok"},
+		{name: "unhealthy", health: "behind", wantErr: true},
+		{name: "slot unavailable", health: "ok", slotErr: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := &solana{client: newStandInClient(t, tt.health, tt.slotErr)}
+			manager := &solanaConnectionManager{chain: chain}
+
+			err := manager.CheckConnection(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckConnection() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("no client", func(t *testing.T) {
+		manager := &solanaConnectionManager{chain: &solana{}}
+		if err := manager.CheckConnection(context.Background()); err == nil {
+			t.Fatal("expected an error without client")
+		}
+	})
+}
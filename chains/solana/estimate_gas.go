@@ -2,11 +2,208 @@ package solana
 
 import (
 	"context"
+	"github.com/ClipFinance/relay-lib/chains/solana/solclient"
+	"github.com/ClipFinance/relay-lib/chains/solana/utils"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"math/big"
+	"sort"
 )
 
-// Implement required interface methods
+const (
+	// priorityFeePercentile is the percentile of recent prioritization fees paid by the solver.
+	priorityFeePercentile = 75
+	// defaultMaxPriorityFee is the priority fee cap in micro-lamports per compute unit when the chain sets none.
+	defaultMaxPriorityFee = 1_000_000
+	// computeUnitLimitMargin is the compute unit limit requested relative to the simulated consumption.
+	computeUnitLimitMargin = 120 // 120%
+	// maxComputeUnitLimit is the maximum compute unit limit of a transaction.
+	maxComputeUnitLimit = 1_400_000
+	// maxPrioritizationFeeAccounts is the maximum number of accounts accepted by getRecentPrioritizationFees.
+	maxPrioritizationFeeAccounts = 128
+)
+
+// computeBudget holds the compute budget attached to a solver transaction.
+type computeBudget struct {
+	unitLimit uint32 // Compute unit limit.
+	unitPrice uint64 // Priority fee in micro-lamports per compute unit.
+}
+
+// instructions returns the Compute Budget instructions setting the budget.
+func (b computeBudget) instructions() []utils.Instruction {
+	return []utils.Instruction{
+		utils.NewSetComputeUnitLimitInstruction(b.unitLimit),
+		utils.NewSetComputeUnitPriceInstruction(b.unitPrice),
+	}
+}
+
+// EstimateGas estimates the compute units consumed by a native SOL transfer by simulating it.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - to: the recipient address of the transfer.
+// - value: the amount of lamports to transfer.
+// - data: the memo attached to the transfer, if any.
+//
+// Returns:
+// - uint64: the compute units consumed by the transaction.
+// - error: an error if the client or signer is not initialized, if the simulation fails, or if the transaction would fail.
 func (s *solana) EstimateGas(ctx context.Context, to string, value *big.Int, data []byte) (uint64, error) {
-	// TODO: Implement gas estimation for Solana
-	return 0, nil
+	s.clientMutex.RLock()
+	client := s.client
+	s.clientMutex.RUnlock()
+
+	s.signerMutex.RLock()
+	signer := s.signer
+	s.signerMutex.RUnlock()
+
+	if client == nil || signer == nil {
+		return 0, errors.New("client or signer not initialized")
+	}
+
+	recipient, err := utils.PublicKeyFromBase58(to)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid recipient address")
+	}
+
+	lamports := uint64(0)
+	if value != nil {
+		if !value.IsUint64() {
+			return 0, errors.New("invalid amount")
+		}
+		lamports = value.Uint64()
+	}
+
+	instructions := []utils.Instruction{
+		utils.NewTransferInstruction(signer.Address(), recipient, lamports),
+	}
+	if len(data) > 0 {
+		instructions = append(instructions, utils.NewMemoInstruction(data))
+	}
+
+	return s.simulateComputeUnits(ctx, client, instructions, signer.Address())
+}
+
+// getComputeBudget derives the compute budget of a transaction from its simulated consumption
+// and the recent prioritization fees of the accounts it writes to.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - client: the Solana client.
+// - instructions: the instructions of the transaction, without compute budget instructions.
+// - feePayer: the fee payer of the transaction.
+//
+// Returns:
+// - computeBudget: the compute budget.
+// - error: an error if the simulation fails or the transaction would fail.
+func (s *solana) getComputeBudget(ctx context.Context, client *solclient.Client, instructions []utils.Instruction, feePayer utils.PublicKey) (computeBudget, error) {
+	unitsConsumed, err := s.simulateComputeUnits(ctx, client, instructions, feePayer)
+	if err != nil {
+		return computeBudget{}, err
+	}
+
+	unitLimit := unitsConsumed * computeUnitLimitMargin / 100
+	if unitLimit > maxComputeUnitLimit {
+		unitLimit = maxComputeUnitLimit
+	}
+
+	return computeBudget{
+		unitLimit: uint32(unitLimit),
+		unitPrice: s.getPriorityFee(ctx, client, instructions),
+	}, nil
+}
+
+// simulateComputeUnits simulates the instructions with the maximum compute unit limit and returns the units consumed.
+// Both Compute Budget instructions of the sent transaction are simulated, as they consume compute units as well.
+func (s *solana) simulateComputeUnits(ctx context.Context, client *solclient.Client, instructions []utils.Instruction, feePayer utils.PublicKey) (uint64, error) {
+	withBudget := append(computeBudget{unitLimit: maxComputeUnitLimit}.instructions(), instructions...)
+
+	// The blockhash is replaced by the node during the simulation.
+	tx, err := utils.NewTransaction(withBudget, utils.ZeroAddress, feePayer)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to build transaction")
+	}
+
+	rawTx, err := tx.Serialize()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to serialize transaction")
+	}
+
+	result, err := client.SimulateTransaction(ctx, rawTx, solclient.SimulateOptions{
+		ReplaceRecentBlockhash: true,
+		Commitment:             solclient.CommitmentConfirmed,
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to simulate transaction")
+	}
+
+	if result.Err.IsSet() {
+		return 0, errors.Errorf("transaction simulation failed: %s", result.Err.String())
+	}
+
+	if result.UnitsConsumed == nil {
+		return 0, errors.New("simulation did not report consumed compute units")
+	}
+
+	return *result.UnitsConsumed, nil
+}
+
+// getPriorityFee returns the configured percentile of the recent prioritization fees of the writable accounts,
+// capped by ChainConfig.MaxPriorityFee. It falls back to no priority fee if the fees cannot be loaded.
+func (s *solana) getPriorityFee(ctx context.Context, client *solclient.Client, instructions []utils.Instruction) uint64 {
+	fees, err := client.GetRecentPrioritizationFees(ctx, writableAccounts(instructions))
+	if err != nil {
+		s.logger.WithField("chain", s.config.Name).WithError(err).Warn("Failed to get recent prioritization fees")
+		return 0
+	}
+
+	if len(fees) == 0 {
+		return 0
+	}
+
+	values := make([]uint64, 0, len(fees))
+	for _, fee := range fees {
+		values = append(values, fee.PrioritizationFee)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	price := values[(len(values)-1)*priorityFeePercentile/100]
+
+	maxPrice := s.config.MaxPriorityFee
+	if maxPrice == 0 {
+		maxPrice = defaultMaxPriorityFee
+	}
+
+	if price > maxPrice {
+		s.logger.WithFields(logrus.Fields{
+			"chain":  s.config.Name,
+			"fee":    price,
+			"maxFee": maxPrice,
+		}).Warn("Priority fee exceeds the chain cap, using the cap")
+		price = maxPrice
+	}
+
+	return price
+}
+
+// writableAccounts returns the distinct writable accounts of the instructions, up to the RPC limit.
+func writableAccounts(instructions []utils.Instruction) []string {
+	seen := make(map[utils.PublicKey]bool)
+	var accounts []string
+
+	for _, instruction := range instructions {
+		for _, account := range instruction.Accounts {
+			if !account.IsWritable || seen[account.PublicKey] {
+				continue
+			}
+			seen[account.PublicKey] = true
+			accounts = append(accounts, account.PublicKey.String())
+
+			if len(accounts) == maxPrioritizationFeeAccounts {
+				return accounts
+			}
+		}
+	}
+
+	return accounts
 }
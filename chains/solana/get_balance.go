@@ -2,6 +2,9 @@ package solana
 
 import (
 	"context"
+	"github.com/ClipFinance/relay-lib/chains/solana/solclient"
+	"github.com/ClipFinance/relay-lib/chains/solana/utils"
+	"github.com/pkg/errors"
 	"math/big"
 )
 
@@ -17,6 +20,53 @@ import (
 // - *big.Int: the token balance
 // - error: an error if the balance check fails
 func (s *solana) GetTokenBalance(ctx context.Context, address string, tokenAddress string) (*big.Int, error) {
-	// TODO: Implement token balance retrieval for Solana
-	return nil, nil
+	s.clientMutex.RLock()
+	client := s.client
+	s.clientMutex.RUnlock()
+
+	if client == nil {
+		return nil, errors.New("client not initialized")
+	}
+
+	if _, err := utils.PublicKeyFromBase58(address); err != nil {
+		return nil, errors.Wrap(err, "invalid address")
+	}
+
+	// Check if requesting native token balance
+	if utils.IsNativeToken(tokenAddress) {
+		balance, err := client.GetBalance(ctx, address, solclient.CommitmentConfirmed)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get native token balance")
+		}
+		return balance, nil
+	}
+
+	if _, err := utils.PublicKeyFromBase58(tokenAddress); err != nil {
+		return nil, errors.Wrap(err, "invalid token address")
+	}
+
+	// For SPL tokens, sum all accounts of the mint held under both token programs
+	balance := new(big.Int)
+	for _, programID := range []utils.PublicKey{utils.TokenProgramID, utils.Token2022ProgramID} {
+		accounts, err := client.GetTokenAccountsByOwner(ctx, address, solclient.TokenAccountsFilter{
+			ProgramID: programID.String(),
+		}, solclient.CommitmentConfirmed)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get token accounts")
+		}
+
+		for _, account := range accounts {
+			if account.Mint != tokenAddress {
+				continue
+			}
+
+			amount, ok := new(big.Int).SetString(account.Amount.Amount, 10)
+			if !ok {
+				return nil, errors.Errorf("invalid amount %q in token account %s", account.Amount.Amount, account.Pubkey)
+			}
+			balance.Add(balance, amount)
+		}
+	}
+
+	return balance, nil
 }
@@ -0,0 +1,419 @@
+package handler
+
+import (
+	"context"
+	"github.com/ClipFinance/relay-lib/chains/solana/solclient"
+	"github.com/ClipFinance/relay-lib/chains/solana/utils"
+	"github.com/ClipFinance/relay-lib/common/checkpoint"
+	relaytypes "github.com/ClipFinance/relay-lib/common/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+// Constants for event handler timeouts and retry attempts.
+const (
+	contextTimeout          = 30 * time.Second // Timeout for context operations.
+	reconnectTimeout        = 5 * time.Second  // Timeout for reconnect attempts.
+	retryTimeout            = 5 * time.Minute  // Timeout for retry operations.
+	maxReconnectAttempts    = 3                // Maximum number of reconnect attempts.
+	accountsRefreshInterval = time.Minute      // Interval for refreshing the watched token accounts.
+	failedRetryInterval     = 10 * time.Second // Interval for retrying notified transactions that failed to process.
+	maxProcessedSignatures  = 10000            // Number of recently processed signatures kept to skip duplicates.
+	checkpointKeyPrefix     = "solana:last_signature:"
+)
+
+// EventHandler handles chain events with thread-safe access.
+// It watches the solver wallet and its token accounts for deposits, using logsSubscribe or signature polling.
+type EventHandler struct {
+	ctx               context.Context                        // Context for managing lifecycle.
+	cancel            context.CancelFunc                     // Cancel function for context.
+	chainConfig       *relaytypes.ChainConfig                // Chain configuration.
+	logger            *logrus.Logger                         // Logger for logging events.
+	client            *solclient.Client                      // Solana client.
+	clientMutex       sync.RWMutex                           // Mutex for the client.
+	solverAddress     string                                 // Solver address.
+	eventChan         chan relaytypes.ChainEvent             // Channel for chain events.
+	checkpoints       relaytypes.CheckpointStore             // Store for the last seen signature per address.
+	watchedAccounts   map[string]bool                        // Watched addresses, true if the full history must be processed.
+	watchedMutex      sync.RWMutex                           // Mutex for watched accounts.
+	lastRefresh       time.Time                              // Time of the last watched accounts refresh.
+	wsClient          *solclient.WSClient                    // PubSub client.
+	sessionCtx        context.Context                        // Context of the current PubSub connection.
+	sessionCancel     context.CancelFunc                     // Cancel function for the PubSub connection context.
+	subscriptions     map[string]*solclient.LogsSubscription // Log subscriptions per watched address.
+	subscriptionMutex sync.Mutex                             // Mutex for the PubSub client and subscriptions.
+	notificationChan  chan addressNotification               // Channel for notifications of all subscriptions.
+	subscriptionErr   chan error                             // Channel for subscription errors.
+	processed         map[string]struct{}                    // Recently processed signatures.
+	processedOrder    []string                               // Processed signatures in insertion order.
+	processedMutex    sync.Mutex                             // Mutex for processed signatures.
+	failedSignatures  map[string][]string                    // Notified signatures per address that failed to process.
+	heldCheckpoints   map[string]string                      // Last notified signature per address with failed signatures.
+	failedMutex       sync.Mutex                             // Mutex for failed signatures and held checkpoints.
+	pollingTicker     *time.Ticker                           // Ticker for polling.
+}
+
+// NewEventHandler creates a new event handler instance.
+//
+// Parameters:
+// - ctx: context for managing the lifecycle of the event handler.
+// - config: the chain configuration.
+// - logger: the logger for logging events.
+// - client: the Solana client.
+// - solverAddr: the solver address.
+// - eventChan: the channel to receive chain events.
+//
+// Returns:
+// - *EventHandler: a new EventHandler instance.
+// - error: an error if any issue occurs during creation.
+func NewEventHandler(
+	ctx context.Context,
+	config *relaytypes.ChainConfig,
+	logger *logrus.Logger,
+	client *solclient.Client,
+	solverAddr string,
+	eventChan chan relaytypes.ChainEvent,
+) (*EventHandler, error) {
+	if _, err := utils.PublicKeyFromBase58(solverAddr); err != nil {
+		return nil, errors.Wrap(err, "invalid solver address")
+	}
+
+	checkpoints := config.CheckpointStore
+	if checkpoints == nil {
+		checkpoints = checkpoint.NewMemoryStore()
+	}
+
+	handlerCtx, cancel := context.WithCancel(ctx)
+
+	handler := &EventHandler{
+		chainConfig:      config,
+		logger:           logger,
+		ctx:              handlerCtx,
+		cancel:           cancel,
+		client:           client,
+		solverAddress:    solverAddr,
+		eventChan:        eventChan,
+		checkpoints:      checkpoints,
+		watchedAccounts:  make(map[string]bool),
+		subscriptions:    make(map[string]*solclient.LogsSubscription),
+		notificationChan: make(chan addressNotification),
+		subscriptionErr:  make(chan error, 1),
+		processed:        make(map[string]struct{}),
+		failedSignatures: make(map[string][]string),
+		heldCheckpoints:  make(map[string]string),
+	}
+
+	return handler, nil
+}
+
+// UpdateClient updates the Solana client and restarts subscriptions and polling.
+//
+// Parameters:
+// - client: the new Solana client.
+func (h *EventHandler) UpdateClient(client *solclient.Client) {
+	h.cancel()
+	h.closeSubscriptions()
+
+	handlerCtx, cancel := context.WithCancel(context.Background())
+	h.ctx = handlerCtx
+	h.cancel = cancel
+
+	h.clientMutex.Lock()
+	h.client = client
+	h.clientMutex.Unlock()
+
+	if h.pollingTicker != nil {
+		h.pollingTicker.Stop()
+		if err := h.StartHTTPPolling(); err != nil {
+			h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to restart HTTP polling after client update")
+		}
+		return
+	}
+
+	if err := h.StartWSSubscription(); err != nil {
+		h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to setup subscriptions after client update")
+	}
+}
+
+// Stop stops the event handler and closes subscriptions and polling.
+func (h *EventHandler) Stop() {
+	h.cancel()
+	h.closeSubscriptions()
+	if h.pollingTicker != nil {
+		h.pollingTicker.Stop()
+	}
+}
+
+// getClient returns the current Solana client.
+func (h *EventHandler) getClient() *solclient.Client {
+	h.clientMutex.RLock()
+	defer h.clientMutex.RUnlock()
+	return h.client
+}
+
+// refreshWatchedAccounts loads the token accounts of the solver and adds them to the watched accounts.
+//
+// Parameters:
+// - initial: whether this is the first refresh, accounts found later have their full history processed.
+//
+// Returns:
+// - []string: the newly watched addresses.
+// - error: an error if the token accounts cannot be loaded.
+func (h *EventHandler) refreshWatchedAccounts(initial bool) ([]string, error) {
+	ctx, cancel := context.WithTimeout(h.ctx, contextTimeout)
+	defer cancel()
+
+	addresses := []string{h.solverAddress}
+	for _, programID := range []utils.PublicKey{utils.TokenProgramID, utils.Token2022ProgramID} {
+		accounts, err := h.getClient().GetTokenAccountsByOwner(ctx, h.solverAddress, solclient.TokenAccountsFilter{
+			ProgramID: programID.String(),
+		}, solclient.CommitmentConfirmed)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get solver token accounts")
+		}
+		for _, account := range accounts {
+			addresses = append(addresses, account.Pubkey)
+		}
+	}
+
+	h.watchedMutex.Lock()
+	defer h.watchedMutex.Unlock()
+
+	var added []string
+	for _, address := range addresses {
+		if _, ok := h.watchedAccounts[address]; ok {
+			continue
+		}
+		h.watchedAccounts[address] = !initial
+		added = append(added, address)
+	}
+	h.lastRefresh = time.Now()
+
+	return added, nil
+}
+
+// getWatchedAccounts returns the watched addresses.
+func (h *EventHandler) getWatchedAccounts() []string {
+	h.watchedMutex.RLock()
+	defer h.watchedMutex.RUnlock()
+
+	addresses := make([]string, 0, len(h.watchedAccounts))
+	for address := range h.watchedAccounts {
+		addresses = append(addresses, address)
+	}
+	return addresses
+}
+
+// isWatched reports whether the address is watched and whether its full history must be processed.
+func (h *EventHandler) isWatched(address string) (watched bool, backfill bool) {
+	h.watchedMutex.RLock()
+	defer h.watchedMutex.RUnlock()
+
+	backfill, watched = h.watchedAccounts[address]
+	return watched, backfill
+}
+
+// pollAddress processes the transactions of an address confirmed after its last seen signature, oldest first.
+// Without a checkpoint the newest signature becomes the starting point, unless the address was discovered
+// after the initial refresh, in which case its full history is processed.
+//
+// Parameters:
+// - address: the watched address.
+//
+// Returns:
+// - error: an error if the signatures cannot be loaded or a transaction cannot be processed.
+func (h *EventHandler) pollAddress(address string) error {
+	lastSignature, err := h.checkpoints.GetCheckpoint(h.ctx, h.chainConfig.ChainID, checkpointKeyPrefix+address)
+	if err != nil {
+		return errors.Wrap(err, "failed to get checkpoint")
+	}
+
+	_, backfill := h.isWatched(address)
+	if lastSignature == "" && !backfill {
+		signatures, err := h.getClient().GetSignaturesForAddress(h.ctx, address, solclient.SignaturesForAddressOptions{
+			Limit: 1,
+		}, solclient.CommitmentConfirmed)
+		if err != nil {
+			return errors.Wrap(err, "failed to get signatures for address")
+		}
+		if len(signatures) > 0 {
+			return h.saveCheckpoint(address, signatures[0].Signature)
+		}
+		return nil
+	}
+
+	var pending []solclient.SignatureInfo
+	before := ""
+	for {
+		signatures, err := h.getClient().GetSignaturesForAddress(h.ctx, address, solclient.SignaturesForAddressOptions{
+			Before: before,
+			Until:  lastSignature,
+			Limit:  maxSignaturesPerPage,
+		}, solclient.CommitmentConfirmed)
+		if err != nil {
+			return errors.Wrap(err, "failed to get signatures for address")
+		}
+
+		pending = append(pending, signatures...)
+		if len(signatures) < maxSignaturesPerPage {
+			break
+		}
+		before = signatures[len(signatures)-1].Signature
+	}
+
+	for i := len(pending) - 1; i >= 0; i-- {
+		if !pending[i].Err.IsSet() {
+			if err := h.processSignature(pending[i].Signature); err != nil {
+				return errors.Wrapf(err, "failed to process transaction %s", pending[i].Signature)
+			}
+		}
+
+		if err := h.saveCheckpoint(address, pending[i].Signature); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveCheckpoint stores the last seen signature of an address.
+func (h *EventHandler) saveCheckpoint(address, signature string) error {
+	if err := h.checkpoints.SaveCheckpoint(h.ctx, h.chainConfig.ChainID, checkpointKeyPrefix+address, signature); err != nil {
+		return errors.Wrap(err, "failed to save checkpoint")
+	}
+	return nil
+}
+
+// processSignature loads a transaction and sends an event for each deposit to the solver it contains.
+// Transactions without a deposit or without a memo are skipped.
+//
+// Parameters:
+// - signature: the transaction signature.
+//
+// Returns:
+// - error: an error if the transaction or its block cannot be loaded.
+func (h *EventHandler) processSignature(signature string) error {
+	if h.isProcessed(signature) {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(h.ctx, contextTimeout)
+	defer cancel()
+
+	client := h.getClient()
+
+	tx, err := client.GetTransaction(ctx, signature, solclient.CommitmentConfirmed)
+	if err != nil {
+		return errors.Wrap(err, "failed to get transaction")
+	}
+
+	if tx.Meta == nil {
+		return errors.New("transaction metadata not available")
+	}
+
+	if tx.Meta.Err.IsSet() {
+		h.markProcessed(signature)
+		return nil
+	}
+
+	var deposits []utils.Transfer
+	for _, transfer := range utils.ParseTransfers(tx) {
+		if transfer.Authority == h.solverAddress {
+			continue
+		}
+		if watched, _ := h.isWatched(transfer.Destination); transfer.DestinationOwner == h.solverAddress || watched {
+			deposits = append(deposits, transfer)
+		}
+	}
+
+	if len(deposits) == 0 {
+		h.markProcessed(signature)
+		return nil
+	}
+
+	quoteID, ok := utils.ExtractMemo(tx)
+	if !ok {
+		h.logger.WithFields(logrus.Fields{
+			"chain":     h.chainConfig.Name,
+			"signature": signature,
+		}).Warn("Deposit without memo, skipping")
+		h.markProcessed(signature)
+		return nil
+	}
+
+	block, err := client.GetBlock(ctx, tx.Slot, solclient.CommitmentConfirmed)
+	if err != nil {
+		return errors.Wrap(err, "failed to get block")
+	}
+
+	blockTime := tx.BlockTime
+	if blockTime == nil {
+		blockTime = block.BlockTime
+	}
+
+	var minedAt time.Time
+	if blockTime != nil {
+		minedAt = time.Unix(*blockTime, 0)
+	}
+
+	for _, deposit := range deposits {
+		chainEvent := relaytypes.ChainEvent{
+			ChainID:           h.chainConfig.ChainID,
+			BlockNumber:       tx.Slot,
+			BlockHash:         block.Blockhash,
+			FromTokenAddr:     deposit.Mint,
+			FromAddress:       deposit.Authority,
+			ToAddress:         h.solverAddress,
+			TransactionHash:   signature,
+			QuoteID:           quoteID,
+			FromTxMinedAt:     minedAt,
+			TransactionAmount: deposit.Amount,
+			Metadata: utils.SolanaMetadata{
+				EventType:          deposit.EventType,
+				InstructionIndex:   deposit.InstructionIndex,
+				Mint:               deposit.Mint,
+				SourceAccount:      deposit.Source,
+				DestinationAccount: deposit.Destination,
+			},
+		}
+
+		select {
+		case h.eventChan <- chainEvent:
+		case <-h.ctx.Done():
+			return errors.New("context cancelled while sending event")
+		}
+	}
+
+	h.markProcessed(signature)
+
+	return nil
+}
+
+// isProcessed reports whether the signature was processed recently.
+func (h *EventHandler) isProcessed(signature string) bool {
+	h.processedMutex.Lock()
+	defer h.processedMutex.Unlock()
+
+	_, ok := h.processed[signature]
+	return ok
+}
+
+// markProcessed records the signature as processed, evicting the oldest entries beyond maxProcessedSignatures.
+func (h *EventHandler) markProcessed(signature string) {
+	h.processedMutex.Lock()
+	defer h.processedMutex.Unlock()
+
+	if _, ok := h.processed[signature]; ok {
+		return
+	}
+
+	h.processed[signature] = struct{}{}
+	h.processedOrder = append(h.processedOrder, signature)
+
+	if len(h.processedOrder) > maxProcessedSignatures {
+		delete(h.processed, h.processedOrder[0])
+		h.processedOrder = h.processedOrder[1:]
+	}
+}
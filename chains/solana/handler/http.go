@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+const (
+	// defaultPollingInterval is the default interval for polling events.
+	defaultPollingInterval = 5 * time.Second
+	// maxSignaturesPerPage is the maximum number of signatures fetched in a single getSignaturesForAddress call.
+	maxSignaturesPerPage = 1000
+)
+
+// StartHTTPPolling starts polling the signatures of the solver wallet and its token accounts.
+// It initializes a ticker to poll at regular intervals and processes deposits in a separate goroutine.
+//
+// Returns:
+// - error: an error if any issue occurs during the polling setup.
+func (h *EventHandler) StartHTTPPolling() error {
+	if _, err := h.refreshWatchedAccounts(h.lastRefresh.IsZero()); err != nil {
+		return errors.Wrap(err, "failed to load watched accounts")
+	}
+
+	h.pollingTicker = time.NewTicker(defaultPollingInterval)
+
+	h.logger.WithFields(logrus.Fields{
+		"chain":    h.chainConfig.Name,
+		"interval": defaultPollingInterval,
+	}).Info("Start polling solver SOL and token account signatures")
+
+	go func() {
+		for {
+			select {
+			case <-h.ctx.Done():
+				return
+			case <-h.pollingTicker.C:
+				if err := h.pollEvents(); err != nil {
+					h.logger.WithError(err).Error("Error polling events")
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// pollEvents refreshes the watched accounts when due and processes new transactions of every watched address.
+//
+// Returns:
+// - error: an error if the watched accounts cannot be refreshed.
+func (h *EventHandler) pollEvents() error {
+	h.watchedMutex.RLock()
+	refreshDue := time.Since(h.lastRefresh) >= accountsRefreshInterval
+	h.watchedMutex.RUnlock()
+
+	if refreshDue {
+		if _, err := h.refreshWatchedAccounts(false); err != nil {
+			return errors.Wrap(err, "failed to refresh watched accounts")
+		}
+	}
+
+	for _, address := range h.getWatchedAccounts() {
+		if err := h.pollAddress(address); err != nil {
+			h.logger.WithFields(logrus.Fields{
+				"chain":   h.chainConfig.Name,
+				"address": address,
+			}).WithError(err).Error("Failed to poll address")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,334 @@
+package handler
+
+import (
+	"context"
+	"github.com/ClipFinance/relay-lib/chains/solana/solclient"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// addressNotification is a logs notification tagged with the watched address it was received for.
+type addressNotification struct {
+	address      string
+	notification solclient.LogsNotification
+}
+
+// StartWSSubscription starts a logsSubscribe subscription for the solver wallet and each of its token accounts.
+// It sets up the necessary subscriptions and starts handling events in a separate goroutine.
+//
+// Returns:
+// - error: an error if any issue occurs during the subscription setup.
+func (h *EventHandler) StartWSSubscription() error {
+	if _, err := h.refreshWatchedAccounts(h.lastRefresh.IsZero()); err != nil {
+		return errors.Wrap(err, "failed to load watched accounts")
+	}
+
+	if err := h.setupSubscriptions(); err != nil {
+		return errors.Wrap(err, "failed to setup subscriptions")
+	}
+
+	go h.handleEvents(h.ctx)
+
+	return nil
+}
+
+// reconnectSubscription attempts to re-establish the subscriptions.
+// It retries the connection up to a maximum number of attempts, with a delay between attempts.
+//
+// Returns:
+// - error: an error if the reconnection fails or the context is cancelled.
+func (h *EventHandler) reconnectSubscription() error {
+	h.closeSubscriptions()
+
+	ticker := time.NewTicker(retryTimeout)
+	defer ticker.Stop()
+
+	for {
+		for attempt := 1; attempt <= maxReconnectAttempts; attempt++ {
+			select {
+			case <-h.ctx.Done():
+				return errors.New("context cancelled during reconnection")
+			default:
+				h.logger.WithFields(logrus.Fields{
+					"chain":   h.chainConfig.Name,
+					"attempt": attempt,
+				}).Info("Attempting to reconnect subscriptions")
+
+				if err := h.setupSubscriptions(); err != nil {
+					h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to reconnect subscriptions")
+
+					if attempt == maxReconnectAttempts {
+						h.logger.WithField("chain", h.chainConfig.Name).Warn("Max reconnect attempts reached, waiting for retry timeout")
+						<-ticker.C
+						attempt = 0
+						continue
+					}
+
+					time.Sleep(reconnectTimeout)
+					continue
+				}
+
+				h.logger.WithField("chain", h.chainConfig.Name).Info("Successfully reconnected subscriptions")
+				return nil
+			}
+		}
+	}
+}
+
+// handleEvents handles incoming notifications, retries the transactions that failed to process,
+// refreshes the watched accounts and attempts to reconnect the subscriptions in case of errors.
+//
+// Parameters:
+// - ctx: the context the subscriptions were started with.
+func (h *EventHandler) handleEvents(ctx context.Context) {
+	refreshTicker := time.NewTicker(accountsRefreshInterval)
+	defer refreshTicker.Stop()
+	retryTicker := time.NewTicker(failedRetryInterval)
+	defer retryTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case err := <-h.subscriptionErr:
+			h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Logs subscription error")
+			if err := h.reconnectSubscription(); err != nil {
+				h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to reconnect logs subscriptions")
+			}
+
+		case event := <-h.notificationChan:
+			h.handleNotification(event)
+
+		case <-retryTicker.C:
+			h.retryFailedSignatures()
+
+		case <-refreshTicker.C:
+			added, err := h.refreshWatchedAccounts(false)
+			if err != nil {
+				h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to refresh watched accounts")
+				continue
+			}
+
+			for _, address := range added {
+				if err := h.subscribeAddress(address); err != nil {
+					h.logger.WithFields(logrus.Fields{
+						"chain":   h.chainConfig.Name,
+						"address": address,
+					}).WithError(err).Error("Failed to subscribe to new token account")
+					continue
+				}
+
+				if err := h.pollAddress(address); err != nil {
+					h.logger.WithFields(logrus.Fields{
+						"chain":   h.chainConfig.Name,
+						"address": address,
+					}).WithError(err).Error("Failed to backfill new token account")
+				}
+			}
+		}
+	}
+}
+
+// handleNotification processes the transaction of a notification and moves the checkpoint of its address to it.
+// A transaction that fails to process, for example because the node does not return it yet, is kept for retry
+// and the checkpoint of its address stays behind it until it is processed.
+//
+// Parameters:
+// - event: the notification with its watched address.
+func (h *EventHandler) handleNotification(event addressNotification) {
+	signature := event.notification.Signature
+
+	if !event.notification.Err.IsSet() {
+		if err := h.processSignature(signature); err != nil {
+			h.logger.WithFields(logrus.Fields{
+				"chain":     h.chainConfig.Name,
+				"signature": signature,
+			}).WithError(err).Error("Failed to process transaction, retrying later")
+
+			h.failedMutex.Lock()
+			h.failedSignatures[event.address] = append(h.failedSignatures[event.address], signature)
+			h.heldCheckpoints[event.address] = signature
+			h.failedMutex.Unlock()
+			return
+		}
+	}
+
+	h.failedMutex.Lock()
+	_, held := h.failedSignatures[event.address]
+	if held {
+		h.heldCheckpoints[event.address] = signature
+	}
+	h.failedMutex.Unlock()
+
+	if held {
+		return
+	}
+
+	if err := h.saveCheckpoint(event.address, signature); err != nil {
+		h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to save checkpoint")
+	}
+}
+
+// retryFailedSignatures processes the transactions that failed to process again. Once all failed transactions
+// of an address are processed, its checkpoint moves to the last signature notified for it.
+func (h *EventHandler) retryFailedSignatures() {
+	h.failedMutex.Lock()
+	defer h.failedMutex.Unlock()
+
+	for address, signatures := range h.failedSignatures {
+		var remaining []string
+		for _, signature := range signatures {
+			if err := h.processSignature(signature); err != nil {
+				h.logger.WithFields(logrus.Fields{
+					"chain":     h.chainConfig.Name,
+					"signature": signature,
+				}).WithError(err).Warn("Failed to process transaction on retry")
+				remaining = append(remaining, signature)
+			}
+		}
+
+		if len(remaining) > 0 {
+			h.failedSignatures[address] = remaining
+			continue
+		}
+
+		if err := h.saveCheckpoint(address, h.heldCheckpoints[address]); err != nil {
+			h.logger.WithField("chain", h.chainConfig.Name).WithError(err).Error("Failed to save checkpoint")
+			continue
+		}
+
+		delete(h.failedSignatures, address)
+		delete(h.heldCheckpoints, address)
+	}
+}
+
+// setupSubscriptions connects to the PubSub endpoint, subscribes to every watched address
+// and processes the transactions missed since the last seen signature of each address.
+//
+// Returns:
+// - error: an error if any issue occurs during the subscription setup.
+func (h *EventHandler) setupSubscriptions() error {
+	h.closeSubscriptions()
+
+	ctx, cancel := context.WithTimeout(h.ctx, contextTimeout)
+	defer cancel()
+
+	wsClient, err := solclient.DialWS(ctx, h.getClient().Endpoint())
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to websocket endpoint")
+	}
+
+	sessionCtx, sessionCancel := context.WithCancel(h.ctx)
+
+	h.subscriptionMutex.Lock()
+	h.wsClient = wsClient
+	h.sessionCtx = sessionCtx
+	h.sessionCancel = sessionCancel
+	h.subscriptionMutex.Unlock()
+
+	addresses := h.getWatchedAccounts()
+	for _, address := range addresses {
+		if err := h.subscribeAddress(address); err != nil {
+			h.closeSubscriptions()
+			return err
+		}
+	}
+
+	// Drop errors of the previous connection.
+	select {
+	case <-h.subscriptionErr:
+	default:
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"chain":    h.chainConfig.Name,
+		"accounts": len(addresses),
+	}).Info("Logs subscriptions established")
+
+	for _, address := range addresses {
+		if err := h.pollAddress(address); err != nil {
+			h.logger.WithFields(logrus.Fields{
+				"chain":   h.chainConfig.Name,
+				"address": address,
+			}).WithError(err).Error("Failed to catch up on address")
+		}
+	}
+
+	return nil
+}
+
+// subscribeAddress subscribes to the transactions mentioning the address and forwards its notifications.
+//
+// Parameters:
+// - address: the watched address.
+//
+// Returns:
+// - error: an error if the subscription fails.
+func (h *EventHandler) subscribeAddress(address string) error {
+	h.subscriptionMutex.Lock()
+	defer h.subscriptionMutex.Unlock()
+
+	if h.wsClient == nil {
+		return errors.New("websocket client not initialized")
+	}
+
+	ctx, cancel := context.WithTimeout(h.sessionCtx, contextTimeout)
+	defer cancel()
+
+	sub, err := h.wsClient.LogsSubscribe(ctx, address, solclient.CommitmentConfirmed)
+	if err != nil {
+		return errors.Wrapf(err, "failed to subscribe to logs of %s", address)
+	}
+
+	h.subscriptions[address] = sub
+	go h.forwardNotifications(h.sessionCtx, address, sub)
+
+	return nil
+}
+
+// forwardNotifications forwards the notifications and errors of a subscription to the handler channels.
+func (h *EventHandler) forwardNotifications(ctx context.Context, address string, sub *solclient.LogsSubscription) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case notification := <-sub.Notifications():
+			select {
+			case h.notificationChan <- addressNotification{address: address, notification: notification}:
+			case <-ctx.Done():
+				return
+			}
+
+		case err := <-sub.Err():
+			if ctx.Err() != nil {
+				return
+			}
+			select {
+			case h.subscriptionErr <- err:
+			default:
+			}
+			return
+		}
+	}
+}
+
+// closeSubscriptions closes the PubSub connection, which drops all of its subscriptions.
+func (h *EventHandler) closeSubscriptions() {
+	h.subscriptionMutex.Lock()
+	defer h.subscriptionMutex.Unlock()
+
+	if h.sessionCancel != nil {
+		h.sessionCancel()
+		h.sessionCancel = nil
+	}
+
+	if h.wsClient != nil {
+		h.wsClient.Close()
+		h.wsClient = nil
+	}
+
+	h.subscriptions = make(map[string]*solclient.LogsSubscription)
+}
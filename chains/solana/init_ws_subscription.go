@@ -2,10 +2,52 @@ package solana
 
 import (
 	"context"
+	"github.com/ClipFinance/relay-lib/chains/solana/handler"
 	"github.com/ClipFinance/relay-lib/common/types"
+	"github.com/pkg/errors"
 )
 
+// InitWSSubscription initializes the WebSocket subscription for the Solana chain.
+//
+// Parameters:
+// - ctx: the context for managing the initialization process.
+// - eventChan: the channel to receive chain events.
+//
+// Returns:
+// - error: an error if the client is not initialized, if the event handler creation fails, or if starting the WebSocket subscription fails.
 func (s *solana) InitWSSubscription(ctx context.Context, eventChan chan types.ChainEvent) error {
-	// TODO: Implement WebSocket subscription for Solana
+	s.eventHandlerMutex.Lock()
+	defer s.eventHandlerMutex.Unlock()
+
+	s.clientMutex.RLock()
+	client := s.client
+	s.clientMutex.RUnlock()
+
+	if client == nil {
+		return errors.New("client not initialized")
+	}
+
+	if s.eventHandler != nil {
+		s.eventHandler.Stop()
+	}
+
+	eventHandler, err := handler.NewEventHandler(
+		ctx,
+		s.config,
+		s.logger,
+		client,
+		s.SolverAddress(),
+		eventChan,
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create event handler")
+	}
+
+	if err := eventHandler.StartWSSubscription(); err != nil {
+		eventHandler.Stop()
+		return errors.Wrap(err, "failed to start event handler")
+	}
+
+	s.eventHandler = eventHandler
 	return nil
 }
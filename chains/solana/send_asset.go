@@ -2,10 +2,250 @@ package solana
 
 import (
 	"context"
+	"encoding/json"
+	"github.com/ClipFinance/relay-lib/chains/solana/solclient"
+	"github.com/ClipFinance/relay-lib/chains/solana/utils"
 	"github.com/ClipFinance/relay-lib/common/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
+// mintInfo holds the mint properties required to build a token transfer.
+type mintInfo struct {
+	programID utils.PublicKey
+	decimals  uint8
+}
+
+// SendAsset sends an asset (native SOL or SPL token) based on the provided transaction intent.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - intent: the transaction intent containing details of the asset transfer.
+//
+// Returns:
+// - *types.Transaction: the transaction details.
+// - error: an error if the client or signer is not initialized or if the transaction fails.
 func (s *solana) SendAsset(ctx context.Context, intent *types.Intent) (*types.Transaction, error) {
-	// TODO: Implement asset sending for Solana
-	return nil, nil
+	s.signerMutex.RLock()
+	signer := s.signer
+	s.signerMutex.RUnlock()
+
+	if signer == nil {
+		return nil, errors.New("signer not initialized")
+	}
+
+	if intent.ToAmount == nil || !intent.ToAmount.IsUint64() {
+		return nil, errors.New("invalid amount")
+	}
+
+	recipient, err := utils.PublicKeyFromBase58(intent.RecipientAddress)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid recipient address")
+	}
+
+	var instructions []utils.Instruction
+	if utils.IsNativeToken(intent.ToToken) {
+		instructions = []utils.Instruction{
+			utils.NewTransferInstruction(signer.Address(), recipient, intent.ToAmount.Uint64()),
+		}
+	} else {
+		instructions, err = s.tokenTransferInstructions(ctx, signer.Address(), recipient, intent.ToToken, intent.ToAmount.Uint64())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	signature, metadata, err := s.signAndSendTransaction(ctx, instructions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.Transaction{
+		Hash:       signature,
+		From:       signer.Address().String(),
+		To:         intent.RecipientAddress,
+		FromAmount: intent.FromAmount.String(),
+		ToAmount:   intent.ToAmount.String(),
+		Token:      intent.ToToken,
+		ChainID:    s.config.ChainID,
+		QuoteID:    intent.QuoteID,
+		Metadata:   metadata,
+	}, nil
+}
+
+// tokenTransferInstructions builds the instructions transferring SPL tokens to the recipient.
+// The recipient's associated token account is created idempotently when it does not exist yet.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - owner: the solver wallet holding the tokens.
+// - recipient: the recipient wallet.
+// - tokenAddress: the base58 encoded mint address.
+// - amount: the amount in base units.
+//
+// Returns:
+// - []utils.Instruction: the transfer instructions.
+// - error: an error if the mint cannot be loaded or the token accounts cannot be derived.
+func (s *solana) tokenTransferInstructions(ctx context.Context, owner, recipient utils.PublicKey, tokenAddress string, amount uint64) ([]utils.Instruction, error) {
+	mint, err := utils.PublicKeyFromBase58(tokenAddress)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid token address")
+	}
+
+	info, err := s.getMintInfo(ctx, tokenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := utils.FindAssociatedTokenAddress(owner, mint, info.programID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to derive source token account")
+	}
+
+	destination, err := utils.FindAssociatedTokenAddress(recipient, mint, info.programID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to derive destination token account")
+	}
+
+	s.clientMutex.RLock()
+	client := s.client
+	s.clientMutex.RUnlock()
+
+	if client == nil {
+		return nil, errors.New("client not initialized")
+	}
+
+	var instructions []utils.Instruction
+
+	_, err = client.GetAccountInfo(ctx, destination.String(), solclient.CommitmentConfirmed)
+	switch {
+	case errors.Is(err, solclient.ErrNotFound):
+		instructions = append(instructions, utils.NewCreateAssociatedTokenAccountIdempotentInstruction(
+			owner, destination, recipient, mint, info.programID,
+		))
+	case err != nil:
+		return nil, errors.Wrap(err, "failed to get destination token account")
+	}
+
+	instructions = append(instructions, utils.NewTransferCheckedInstruction(
+		info.programID, source, mint, destination, owner, amount, info.decimals,
+	))
+
+	return instructions, nil
+}
+
+// getMintInfo loads the token program and decimals of the given mint.
+func (s *solana) getMintInfo(ctx context.Context, tokenAddress string) (*mintInfo, error) {
+	s.clientMutex.RLock()
+	client := s.client
+	s.clientMutex.RUnlock()
+
+	if client == nil {
+		return nil, errors.New("client not initialized")
+	}
+
+	account, err := client.GetAccountInfo(ctx, tokenAddress, solclient.CommitmentConfirmed)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get mint account")
+	}
+
+	programID, err := utils.PublicKeyFromBase58(account.Owner)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid mint owner")
+	}
+
+	if programID != utils.TokenProgramID && programID != utils.Token2022ProgramID {
+		return nil, errors.Errorf("account %s is not a token mint", tokenAddress)
+	}
+
+	var data struct {
+		Parsed struct {
+			Type string `json:"type"`
+			Info struct {
+				Decimals uint8 `json:"decimals"`
+			} `json:"info"`
+		} `json:"parsed"`
+	}
+	if err := json.Unmarshal(account.Data, &data); err != nil {
+		return nil, errors.Wrap(err, "failed to decode mint account")
+	}
+
+	if data.Parsed.Type != "mint" {
+		return nil, errors.Errorf("account %s is not a token mint", tokenAddress)
+	}
+
+	return &mintInfo{
+		programID: programID,
+		decimals:  data.Parsed.Info.Decimals,
+	}, nil
+}
+
+// signAndSendTransaction builds a transaction from the instructions with a compute budget, signs it and submits it.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - instructions: the instructions to include in the transaction.
+//
+// Returns:
+// - string: the base58 encoded transaction signature.
+// - utils.SolanaTxMetadata: the blockhash the transaction was built with.
+// - error: an error if the client or signer is not initialized, or if the signing or sending fails.
+func (s *solana) signAndSendTransaction(ctx context.Context, instructions []utils.Instruction) (string, utils.SolanaTxMetadata, error) {
+	s.clientMutex.RLock()
+	client := s.client
+	s.clientMutex.RUnlock()
+
+	s.signerMutex.RLock()
+	signer := s.signer
+	s.signerMutex.RUnlock()
+
+	if client == nil || signer == nil {
+		return "", utils.SolanaTxMetadata{}, errors.New("client or signer not initialized")
+	}
+
+	blockhash, err := client.GetLatestBlockhash(ctx, solclient.CommitmentConfirmed)
+	if err != nil {
+		return "", utils.SolanaTxMetadata{}, errors.Wrap(err, "failed to get latest blockhash")
+	}
+
+	budget, err := s.getComputeBudget(ctx, client, instructions, signer.Address())
+	if err != nil {
+		return "", utils.SolanaTxMetadata{}, errors.Wrap(err, "failed to get compute budget")
+	}
+
+	metadata := utils.SolanaTxMetadata{
+		RecentBlockhash:      blockhash.Blockhash,
+		LastValidBlockHeight: blockhash.LastValidBlockHeight,
+		ComputeUnitLimit:     budget.unitLimit,
+		ComputeUnitPrice:     budget.unitPrice,
+	}
+
+	tx, err := utils.NewTransaction(append(budget.instructions(), instructions...), blockhash.Blockhash, signer.Address())
+	if err != nil {
+		return "", metadata, errors.Wrap(err, "failed to build transaction")
+	}
+
+	signedTx, err := signer.SignTx(tx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to sign transaction")
+		return "", metadata, errors.Wrap(err, "failed to sign transaction")
+	}
+
+	rawTx, err := signedTx.Serialize()
+	if err != nil {
+		return "", metadata, errors.Wrap(err, "failed to serialize transaction")
+	}
+
+	signature, err := client.SendTransaction(ctx, rawTx, solclient.SendOptions{
+		PreflightCommitment: solclient.CommitmentConfirmed,
+	})
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"chain":     s.config.Name,
+			"signature": signedTx.Signature().String(),
+		}).WithError(err).Error("Failed to send transaction")
+		return "", metadata, errors.Wrap(err, "failed to send transaction")
+	}
+
+	return signature, metadata, nil
 }
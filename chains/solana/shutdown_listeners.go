@@ -6,7 +6,7 @@ func (s *solana) ShutdownListeners() {
 	defer s.eventHandlerMutex.Unlock()
 
 	if s.eventHandler != nil {
-		// TODO: Implement the Stop method for the event handler.
+		s.eventHandler.Stop()
 		s.eventHandler = nil
 	}
 }
@@ -0,0 +1,174 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"github.com/ClipFinance/relay-lib/chains/solana/utils"
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// Signer is an interface that defines methods for signing data and transactions, and retrieving the signer's address.
+type Signer interface {
+	// Sign signs the given data and returns the signature.
+	//
+	// Parameters:
+	// - data: the data to be signed.
+	//
+	// Returns:
+	// - []byte: the Ed25519 signature.
+	// - error: an error if the signing process fails.
+	Sign(data []byte) ([]byte, error)
+
+	// SignTx signs the given transaction and returns the signed transaction.
+	//
+	// Parameters:
+	// - transaction: the transaction to be signed.
+	//
+	// Returns:
+	// - *utils.Transaction: the signed transaction.
+	// - error: an error if the signer is not a required signer of the transaction.
+	SignTx(transaction *utils.Transaction) (*utils.Transaction, error)
+
+	// Address returns the signer's address.
+	//
+	// Returns:
+	// - utils.PublicKey: the signer's public key.
+	Address() utils.PublicKey
+}
+
+// signer is a concrete implementation of the Signer interface.
+type signer struct {
+	privateKey ed25519.PrivateKey
+	address    utils.PublicKey
+}
+
+// NewSigner creates a new signer instance with the given private key.
+//
+// Parameters:
+// - privateKey: the Ed25519 private key to be used for signing.
+//
+// Returns:
+// - Signer: a new signer instance.
+// - error: an error if the private key is not valid.
+func NewSigner(privateKey ed25519.PrivateKey) (Signer, error) {
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return nil, errors.Errorf("invalid private key length, expected: %d, got: %d", ed25519.PrivateKeySize, len(privateKey))
+	}
+
+	pubKey, ok := privateKey.Public().(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.New("cannot assign public key to Ed25519")
+	}
+
+	var address utils.PublicKey
+	copy(address[:], pubKey)
+
+	return &signer{
+		privateKey: privateKey,
+		address:    address,
+	}, nil
+}
+
+// ParsePrivateKey parses a Solana secret key.
+// It accepts a base58 encoded 64-byte keypair or 32-byte seed, and the JSON byte array format produced by solana-keygen.
+//
+// Parameters:
+// - privateKey: the encoded secret key.
+//
+// Returns:
+// - ed25519.PrivateKey: the parsed private key.
+// - error: an error if the key cannot be decoded or the embedded public key does not match.
+func ParsePrivateKey(privateKey string) (ed25519.PrivateKey, error) {
+	privateKey = strings.TrimSpace(privateKey)
+
+	var raw []byte
+	if strings.HasPrefix(privateKey, "[") {
+		var ints []int
+		if err := json.Unmarshal([]byte(privateKey), &ints); err != nil {
+			return nil, errors.Wrap(err, "failed to decode JSON keypair")
+		}
+		for _, v := range ints {
+			if v < 0 || v > 255 {
+				return nil, errors.New("invalid byte value in JSON keypair")
+			}
+			raw = append(raw, byte(v))
+		}
+	} else {
+		decoded, err := base58.Decode(privateKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode base58 private key")
+		}
+		raw = decoded
+	}
+
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		key := ed25519.NewKeyFromSeed(raw[:ed25519.SeedSize])
+		if !bytes.Equal(key[ed25519.SeedSize:], raw[ed25519.SeedSize:]) {
+			return nil, errors.New("public key does not match secret key")
+		}
+		return key, nil
+	default:
+		return nil, errors.Errorf("invalid private key length: %d", len(raw))
+	}
+}
+
+// Sign signs the given data and returns the signature.
+//
+// Parameters:
+// - data: the data to be signed.
+//
+// Returns:
+// - []byte: the Ed25519 signature.
+// - error: an error if the signing process fails.
+func (s *signer) Sign(data []byte) ([]byte, error) {
+	return ed25519.Sign(s.privateKey, data), nil
+}
+
+// Address returns the signer's address.
+//
+// Returns:
+// - utils.PublicKey: the signer's public key.
+func (s *signer) Address() utils.PublicKey {
+	return s.address
+}
+
+// SignTx signs the given transaction and returns the signed transaction.
+//
+// Parameters:
+// - tx: the transaction to be signed.
+//
+// Returns:
+// - *utils.Transaction: the signed transaction.
+// - error: an error if the signer is not a required signer of the transaction.
+func (s *signer) SignTx(tx *utils.Transaction) (*utils.Transaction, error) {
+	index := -1
+	for i, key := range tx.Signers() {
+		if key == s.address {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, errors.New("signer is not a required signer of the transaction")
+	}
+
+	signature, err := s.Sign(tx.Message.Serialize())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign transaction")
+	}
+
+	signedTx := &utils.Transaction{
+		Signatures: make([]utils.Signature, len(tx.Signers())),
+		Message:    tx.Message,
+	}
+	copy(signedTx.Signatures, tx.Signatures)
+	copy(signedTx.Signatures[index][:], signature)
+
+	return signedTx, nil
+}
@@ -0,0 +1,508 @@
+package solclient
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"math/big"
+	"net/url"
+	"strings"
+)
+
+// ErrNotFound is returned when the requested transaction or account does not exist.
+var ErrNotFound = errors.New("not found")
+
+const (
+	// defaultHTTPPort is the default JSON-RPC port of a local Solana validator.
+	defaultHTTPPort = "8899"
+	// defaultWSPort is the default PubSub port of a local Solana validator.
+	defaultWSPort = "8900"
+)
+
+// Client is a typed Solana JSON-RPC client.
+type Client struct {
+	rpcClient *rpc.Client // Underlying JSON-RPC client.
+	endpoint  string      // HTTP endpoint of the node.
+}
+
+// Dial connects a client to the given Solana JSON-RPC endpoint.
+// WebSocket URLs are converted to their HTTP counterpart.
+//
+// Parameters:
+// - rawurl: the URL of the Solana node.
+//
+// Returns:
+// - *Client: a new Client instance.
+// - error: an error if the endpoint cannot be dialed.
+func Dial(rawurl string) (*Client, error) {
+	return DialContext(context.Background(), rawurl)
+}
+
+// DialContext connects a client to the given Solana JSON-RPC endpoint using the provided context.
+//
+// Parameters:
+// - ctx: the context for managing the dial.
+// - rawurl: the URL of the Solana node.
+//
+// Returns:
+// - *Client: a new Client instance.
+// - error: an error if the endpoint cannot be dialed.
+func DialContext(ctx context.Context, rawurl string) (*Client, error) {
+	endpoint := HTTPEndpoint(rawurl)
+
+	rpcClient, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to dial solana rpc")
+	}
+
+	return &Client{
+		rpcClient: rpcClient,
+		endpoint:  endpoint,
+	}, nil
+}
+
+// Close closes the underlying RPC connection.
+func (c *Client) Close() {
+	c.rpcClient.Close()
+}
+
+// Endpoint returns the HTTP endpoint the client is connected to.
+//
+// Returns:
+// - string: the HTTP endpoint.
+func (c *Client) Endpoint() string {
+	return c.endpoint
+}
+
+// GetHealth checks the health of the node.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+//
+// Returns:
+// - error: an error if the node is unhealthy or unreachable.
+func (c *Client) GetHealth(ctx context.Context) error {
+	var result string
+	if err := c.rpcClient.CallContext(ctx, &result, "getHealth"); err != nil {
+		return errors.Wrap(err, "getHealth failed")
+	}
+
+	if result != "ok" {
+		return errors.Errorf("node is unhealthy: %s", result)
+	}
+
+	return nil
+}
+
+// GetSlot returns the slot that has reached the given commitment level.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - commitment: the commitment level.
+//
+// Returns:
+// - uint64: the current slot.
+// - error: an error if the request fails.
+func (c *Client) GetSlot(ctx context.Context, commitment Commitment) (uint64, error) {
+	var slot uint64
+	if err := c.rpcClient.CallContext(ctx, &slot, "getSlot", commitmentConfig(commitment)); err != nil {
+		return 0, errors.Wrap(err, "getSlot failed")
+	}
+
+	return slot, nil
+}
+
+// GetBlockHeight returns the block height that has reached the given commitment level.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - commitment: the commitment level.
+//
+// Returns:
+// - uint64: the current block height.
+// - error: an error if the request fails.
+func (c *Client) GetBlockHeight(ctx context.Context, commitment Commitment) (uint64, error) {
+	var height uint64
+	if err := c.rpcClient.CallContext(ctx, &height, "getBlockHeight", commitmentConfig(commitment)); err != nil {
+		return 0, errors.Wrap(err, "getBlockHeight failed")
+	}
+
+	return height, nil
+}
+
+// GetBalance returns the lamport balance of the given account.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - address: the base58 encoded account address.
+// - commitment: the commitment level.
+//
+// Returns:
+// - *big.Int: the balance in lamports.
+// - error: an error if the request fails.
+func (c *Client) GetBalance(ctx context.Context, address string, commitment Commitment) (*big.Int, error) {
+	var result contextResult
+	if err := c.rpcClient.CallContext(ctx, &result, "getBalance", address, commitmentConfig(commitment)); err != nil {
+		return nil, errors.Wrap(err, "getBalance failed")
+	}
+
+	var lamports uint64
+	if err := json.Unmarshal(result.Value, &lamports); err != nil {
+		return nil, errors.Wrap(err, "failed to decode balance")
+	}
+
+	return new(big.Int).SetUint64(lamports), nil
+}
+
+// GetAccountInfo returns the account at the given address in jsonParsed encoding.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - address: the base58 encoded account address.
+// - commitment: the commitment level.
+//
+// Returns:
+// - *AccountInfo: the account details.
+// - error: ErrNotFound if the account does not exist, or an error if the request fails.
+func (c *Client) GetAccountInfo(ctx context.Context, address string, commitment Commitment) (*AccountInfo, error) {
+	config := commitmentConfig(commitment)
+	config["encoding"] = "jsonParsed"
+
+	var result contextResult
+	if err := c.rpcClient.CallContext(ctx, &result, "getAccountInfo", address, config); err != nil {
+		return nil, errors.Wrap(err, "getAccountInfo failed")
+	}
+
+	var account *AccountInfo
+	if err := json.Unmarshal(result.Value, &account); err != nil {
+		return nil, errors.Wrap(err, "failed to decode account info")
+	}
+
+	if account == nil {
+		return nil, ErrNotFound
+	}
+
+	return account, nil
+}
+
+// GetLatestBlockhash returns the latest blockhash and the last block height at which it is valid.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - commitment: the commitment level.
+//
+// Returns:
+// - *LatestBlockhash: the latest blockhash.
+// - error: an error if the request fails.
+func (c *Client) GetLatestBlockhash(ctx context.Context, commitment Commitment) (*LatestBlockhash, error) {
+	var result contextResult
+	if err := c.rpcClient.CallContext(ctx, &result, "getLatestBlockhash", commitmentConfig(commitment)); err != nil {
+		return nil, errors.Wrap(err, "getLatestBlockhash failed")
+	}
+
+	var blockhash LatestBlockhash
+	if err := json.Unmarshal(result.Value, &blockhash); err != nil {
+		return nil, errors.Wrap(err, "failed to decode latest blockhash")
+	}
+
+	return &blockhash, nil
+}
+
+// GetTokenAccountsByOwner returns the SPL token accounts owned by the given wallet.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - owner: the base58 encoded wallet address.
+// - filter: the mint or token program to filter accounts by.
+// - commitment: the commitment level.
+//
+// Returns:
+// - []TokenAccount: the token accounts of the owner.
+// - error: an error if the request fails.
+func (c *Client) GetTokenAccountsByOwner(ctx context.Context, owner string, filter TokenAccountsFilter, commitment Commitment) ([]TokenAccount, error) {
+	filterParam := map[string]string{}
+	switch {
+	case filter.Mint != "":
+		filterParam["mint"] = filter.Mint
+	case filter.ProgramID != "":
+		filterParam["programId"] = filter.ProgramID
+	default:
+		return nil, errors.New("either mint or program id filter is required")
+	}
+
+	config := commitmentConfig(commitment)
+	config["encoding"] = "jsonParsed"
+
+	var result contextResult
+	if err := c.rpcClient.CallContext(ctx, &result, "getTokenAccountsByOwner", owner, filterParam, config); err != nil {
+		return nil, errors.Wrap(err, "getTokenAccountsByOwner failed")
+	}
+
+	var rawAccounts []rawTokenAccount
+	if err := json.Unmarshal(result.Value, &rawAccounts); err != nil {
+		return nil, errors.Wrap(err, "failed to decode token accounts")
+	}
+
+	accounts := make([]TokenAccount, 0, len(rawAccounts))
+	for _, raw := range rawAccounts {
+		info := raw.Account.Data.Parsed.Info
+		accounts = append(accounts, TokenAccount{
+			Pubkey:    raw.Pubkey,
+			ProgramID: raw.Account.Owner,
+			Mint:      info.Mint,
+			Owner:     info.Owner,
+			Amount:    info.TokenAmount,
+		})
+	}
+
+	return accounts, nil
+}
+
+// GetSignatureStatuses returns the statuses of the given transaction signatures.
+// Unknown signatures have a nil entry in the result.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - signatures: the base58 encoded transaction signatures.
+// - searchTransactionHistory: whether to search the ledger beyond the recent status cache.
+//
+// Returns:
+// - []*SignatureStatus: the statuses in the same order as the signatures.
+// - error: an error if the request fails.
+func (c *Client) GetSignatureStatuses(ctx context.Context, signatures []string, searchTransactionHistory bool) ([]*SignatureStatus, error) {
+	var result contextResult
+	if err := c.rpcClient.CallContext(ctx, &result, "getSignatureStatuses", signatures, map[string]interface{}{
+		"searchTransactionHistory": searchTransactionHistory,
+	}); err != nil {
+		return nil, errors.Wrap(err, "getSignatureStatuses failed")
+	}
+
+	var statuses []*SignatureStatus
+	if err := json.Unmarshal(result.Value, &statuses); err != nil {
+		return nil, errors.Wrap(err, "failed to decode signature statuses")
+	}
+
+	return statuses, nil
+}
+
+// GetTransaction returns the confirmed transaction with the given signature in jsonParsed encoding.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - signature: the base58 encoded transaction signature.
+// - commitment: the commitment level, processed is not supported by the node.
+//
+// Returns:
+// - *TransactionResult: the transaction details.
+// - error: ErrNotFound if the transaction is unknown, or an error if the request fails.
+func (c *Client) GetTransaction(ctx context.Context, signature string, commitment Commitment) (*TransactionResult, error) {
+	config := commitmentConfig(commitment)
+	config["encoding"] = "jsonParsed"
+	config["maxSupportedTransactionVersion"] = 0
+
+	var result *TransactionResult
+	if err := c.rpcClient.CallContext(ctx, &result, "getTransaction", signature, config); err != nil {
+		return nil, errors.Wrap(err, "getTransaction failed")
+	}
+
+	if result == nil {
+		return nil, ErrNotFound
+	}
+
+	return result, nil
+}
+
+// GetSignaturesForAddress returns the signatures of confirmed transactions referencing the given address, newest first.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - address: the base58 encoded account address.
+// - opts: the paging options.
+// - commitment: the commitment level, processed is not supported by the node.
+//
+// Returns:
+// - []SignatureInfo: the transaction signatures.
+// - error: an error if the request fails.
+func (c *Client) GetSignaturesForAddress(ctx context.Context, address string, opts SignaturesForAddressOptions, commitment Commitment) ([]SignatureInfo, error) {
+	config := commitmentConfig(commitment)
+	if opts.Before != "" {
+		config["before"] = opts.Before
+	}
+	if opts.Until != "" {
+		config["until"] = opts.Until
+	}
+	if opts.Limit > 0 {
+		config["limit"] = opts.Limit
+	}
+
+	var result []SignatureInfo
+	if err := c.rpcClient.CallContext(ctx, &result, "getSignaturesForAddress", address, config); err != nil {
+		return nil, errors.Wrap(err, "getSignaturesForAddress failed")
+	}
+
+	return result, nil
+}
+
+// GetBlock returns the header data of the confirmed block at the given slot, without transactions and rewards.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - slot: the slot of the block.
+// - commitment: the commitment level, processed is not supported by the node.
+//
+// Returns:
+// - *Block: the block header data.
+// - error: ErrNotFound if the slot was skipped or is not available, or an error if the request fails.
+func (c *Client) GetBlock(ctx context.Context, slot uint64, commitment Commitment) (*Block, error) {
+	config := commitmentConfig(commitment)
+	config["transactionDetails"] = "none"
+	config["rewards"] = false
+	config["maxSupportedTransactionVersion"] = 0
+
+	var result *Block
+	if err := c.rpcClient.CallContext(ctx, &result, "getBlock", slot, config); err != nil {
+		return nil, errors.Wrap(err, "getBlock failed")
+	}
+
+	if result == nil {
+		return nil, ErrNotFound
+	}
+
+	return result, nil
+}
+
+// SendTransaction submits a signed, serialized transaction to the cluster.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - rawTx: the serialized signed transaction.
+// - opts: the send options.
+//
+// Returns:
+// - string: the base58 encoded signature of the transaction.
+// - error: an error if the node rejects the transaction.
+func (c *Client) SendTransaction(ctx context.Context, rawTx []byte, opts SendOptions) (string, error) {
+	config := map[string]interface{}{
+		"encoding":      "base64",
+		"skipPreflight": opts.SkipPreflight,
+	}
+	if opts.PreflightCommitment != "" {
+		config["preflightCommitment"] = opts.PreflightCommitment
+	}
+	if opts.MaxRetries != nil {
+		config["maxRetries"] = *opts.MaxRetries
+	}
+
+	var signature string
+	if err := c.rpcClient.CallContext(ctx, &signature, "sendTransaction", base64.StdEncoding.EncodeToString(rawTx), config); err != nil {
+		return "", errors.Wrap(err, "sendTransaction failed")
+	}
+
+	return signature, nil
+}
+
+// SimulateTransaction simulates a serialized transaction without submitting it.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - rawTx: the serialized transaction, signatures are only checked with SigVerify.
+// - opts: the simulation options.
+//
+// Returns:
+// - *SimulationResult: the simulation outcome, including the transaction error if it would fail.
+// - error: an error if the request fails.
+func (c *Client) SimulateTransaction(ctx context.Context, rawTx []byte, opts SimulateOptions) (*SimulationResult, error) {
+	config := commitmentConfig(opts.Commitment)
+	config["encoding"] = "base64"
+	config["sigVerify"] = opts.SigVerify
+	config["replaceRecentBlockhash"] = opts.ReplaceRecentBlockhash
+
+	var result contextResult
+	if err := c.rpcClient.CallContext(ctx, &result, "simulateTransaction", base64.StdEncoding.EncodeToString(rawTx), config); err != nil {
+		return nil, errors.Wrap(err, "simulateTransaction failed")
+	}
+
+	var simulation SimulationResult
+	if err := json.Unmarshal(result.Value, &simulation); err != nil {
+		return nil, errors.Wrap(err, "failed to decode simulation result")
+	}
+
+	return &simulation, nil
+}
+
+// GetRecentPrioritizationFees returns the prioritization fees observed in recent slots.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - accounts: the writable accounts the fees are restricted to, at most 128.
+//
+// Returns:
+// - []PrioritizationFee: the fee per recent slot.
+// - error: an error if the request fails.
+func (c *Client) GetRecentPrioritizationFees(ctx context.Context, accounts []string) ([]PrioritizationFee, error) {
+	if accounts == nil {
+		accounts = []string{}
+	}
+
+	var result []PrioritizationFee
+	if err := c.rpcClient.CallContext(ctx, &result, "getRecentPrioritizationFees", accounts); err != nil {
+		return nil, errors.Wrap(err, "getRecentPrioritizationFees failed")
+	}
+
+	return result, nil
+}
+
+// HTTPEndpoint converts a WebSocket endpoint to the matching HTTP JSON-RPC endpoint.
+// HTTP endpoints are returned unchanged.
+//
+// Parameters:
+// - rawurl: the node URL.
+//
+// Returns:
+// - string: the HTTP endpoint.
+func HTTPEndpoint(rawurl string) string {
+	return convertEndpoint(rawurl, map[string]string{"ws": "http", "wss": "https"}, defaultWSPort, defaultHTTPPort)
+}
+
+// WSEndpoint converts an HTTP endpoint to the matching WebSocket PubSub endpoint.
+// WebSocket endpoints are returned unchanged.
+//
+// Parameters:
+// - rawurl: the node URL.
+//
+// Returns:
+// - string: the WebSocket endpoint.
+func WSEndpoint(rawurl string) string {
+	return convertEndpoint(rawurl, map[string]string{"http": "ws", "https": "wss"}, defaultHTTPPort, defaultWSPort)
+}
+
+// convertEndpoint rewrites the scheme of the URL and, for the default local validator ports, the port.
+func convertEndpoint(rawurl string, schemes map[string]string, fromPort, toPort string) string {
+	parsed, err := url.Parse(rawurl)
+	if err != nil {
+		return rawurl
+	}
+
+	scheme, ok := schemes[strings.ToLower(parsed.Scheme)]
+	if !ok {
+		return rawurl
+	}
+	parsed.Scheme = scheme
+
+	if parsed.Port() == fromPort {
+		parsed.Host = parsed.Hostname() + ":" + toPort
+	}
+
+	return parsed.String()
+}
+
+// commitmentConfig builds the configuration object carrying the commitment level.
+func commitmentConfig(commitment Commitment) map[string]interface{} {
+	config := map[string]interface{}{}
+	if commitment != "" {
+		config["commitment"] = commitment
+	}
+	return config
+}
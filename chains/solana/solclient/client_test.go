@@ -0,0 +1,170 @@
+package solclient
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// rpcError is a JSON-RPC error answered by the stand-in node.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// standInMethod answers a JSON-RPC method with a result or an error.
+type standInMethod func(params json.RawMessage) (interface{}, *rpcError)
+
+// newStandIn starts an httptest server speaking Solana JSON-RPC with the given methods.
+func newStandIn(t *testing.T, methods map[string]standInMethod) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		response := map[string]interface{}{"jsonrpc": "2.0", "id": request.ID}
+		method, ok := methods[request.Method]
+		if !ok {
+			response["error"] = &rpcError{Code: -32601, Message: "Method not found"}
+		} else if result, rpcErr := method(request.Params); rpcErr != nil {
+			response["error"] = rpcErr
+		} else {
+			response["result"] = result
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := Dial(server.URL)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	return client
+}
+
+func TestGetTransactionNotFound(t *testing.T) {
+	client := newStandIn(t, map[string]standInMethod{
+		"getTransaction": func(json.RawMessage) (interface{}, *rpcError) { return nil, nil },
+	})
+
+	_, err := client.GetTransaction(context.Background(), "sig", CommitmentConfirmed)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("GetTransaction() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetTransaction(t *testing.T) {
+	client := newStandIn(t, map[string]standInMethod{
+		"getTransaction": func(params json.RawMessage) (interface{}, *rpcError) {
+			var args []interface{}
+			if err := json.Unmarshal(params, &args); err != nil || len(args) != 2 || args[0] != "sig" {
+				return nil, &rpcError{Code: -32602, Message: "Invalid params"}
+			}
+			return map[string]interface{}{"slot": 42, "blockTime": 1700000000}, nil
+		},
+	})
+
+	result, err := client.GetTransaction(context.Background(), "sig", CommitmentConfirmed)
+	if err != nil {
+		t.Fatalf("GetTransaction() error = %v", err)
+	}
+	if result.Slot != 42 {
+		t.Fatalf("GetTransaction() slot = %d, want 42", result.Slot)
+	}
+}
+
+func TestRPCErrorMapping(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(*Client) error
+		code int
+	}{
+		{
+			name: "getHealth unhealthy",
+			call: func(c *Client) error { return c.GetHealth(context.Background()) },
+			code: -32005,
+		},
+		{
+			name: "getSlot unknown method",
+			call: func(c *Client) error {
+				_, err := c.GetSlot(context.Background(), CommitmentProcessed)
+				return err
+			},
+			code: -32601,
+		},
+		{
+			name: "sendTransaction preflight failure",
+			call: func(c *Client) error {
+				_, err := c.SendTransaction(context.Background(), []byte{1}, SendOptions{})
+				return err
+			},
+			code: -32002,
+		},
+	}
+
+	client := newStandIn(t, map[string]standInMethod{
+		"getHealth": func(json.RawMessage) (interface{}, *rpcError) {
+			return nil, &rpcError{Code: -32005, Message: "Node is unhealthy"}
+		},
+		"sendTransaction": func(json.RawMessage) (interface{}, *rpcError) {
+			return nil, &rpcError{Code: -32002, Message: "Transaction simulation failed"}
+		},
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(client)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+
+			var rpcErr rpc.Error
+			if !errors.As(err, &rpcErr) {
+				t.Fatalf("error %v does not carry the JSON-RPC error", err)
+			}
+			if rpcErr.ErrorCode() != tt.code {
+				t.Fatalf("error code = %d, want %d", rpcErr.ErrorCode(), tt.code)
+			}
+		})
+	}
+}
+
+func TestGetHealth(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  string
+		wantErr bool
+	}{
+		{name: "ok", result: "ok"},
+		{name: "behind", result: "behind", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newStandIn(t, map[string]standInMethod{
+				"getHealth": func(json.RawMessage) (interface{}, *rpcError) { return tt.result, nil },
+			})
+
+			err := client.GetHealth(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetHealth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
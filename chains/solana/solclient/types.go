@@ -0,0 +1,302 @@
+package solclient
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Commitment represents the commitment level used when querying the Solana cluster.
+type Commitment string
+
+const (
+	// CommitmentProcessed queries the most recent block processed by the node.
+	CommitmentProcessed Commitment = "processed"
+	// CommitmentConfirmed queries the most recent block voted on by a supermajority of the cluster.
+	CommitmentConfirmed Commitment = "confirmed"
+	// CommitmentFinalized queries the most recent block finalized by the cluster.
+	CommitmentFinalized Commitment = "finalized"
+)
+
+// TransactionError holds the raw JSON error reported by the cluster for a failed transaction.
+type TransactionError json.RawMessage
+
+// IsSet reports whether the error holds a non-null value.
+func (e TransactionError) IsSet() bool {
+	return len(e) > 0 && !bytes.Equal(e, []byte("null"))
+}
+
+// String returns the JSON representation of the error.
+func (e TransactionError) String() string {
+	return string(e)
+}
+
+// UnmarshalJSON stores the raw error payload.
+func (e *TransactionError) UnmarshalJSON(data []byte) error {
+	*e = append((*e)[0:0], data...)
+	return nil
+}
+
+// TokenAmount represents an SPL token amount in raw base units together with the mint decimals.
+//
+// Fields:
+// - Amount: the raw amount in base units.
+// - Decimals: the number of decimals of the mint.
+// - UIAmountString: the amount formatted using the mint decimals.
+type TokenAmount struct {
+	Amount         string `json:"amount"`
+	Decimals       uint8  `json:"decimals"`
+	UIAmountString string `json:"uiAmountString"`
+}
+
+// TokenAccount represents an SPL token account returned by getTokenAccountsByOwner.
+//
+// Fields:
+// - Pubkey: the address of the token account.
+// - ProgramID: the token program owning the account (Token or Token-2022).
+// - Mint: the mint of the token held by the account.
+// - Owner: the wallet owning the token account.
+// - Amount: the token amount held by the account.
+type TokenAccount struct {
+	Pubkey    string
+	ProgramID string
+	Mint      string
+	Owner     string
+	Amount    TokenAmount
+}
+
+// TokenAccountsFilter selects token accounts either by mint or by token program.
+// Exactly one of the fields must be set.
+type TokenAccountsFilter struct {
+	Mint      string
+	ProgramID string
+}
+
+// SignatureStatus represents the status of a transaction signature.
+//
+// Fields:
+// - Slot: the slot the transaction was processed in.
+// - Confirmations: the number of blocks since confirmation, nil once rooted.
+// - Err: the error if the transaction failed.
+// - ConfirmationStatus: the cluster confirmation status of the transaction.
+type SignatureStatus struct {
+	Slot               uint64           `json:"slot"`
+	Confirmations      *uint64          `json:"confirmations"`
+	Err                TransactionError `json:"err"`
+	ConfirmationStatus Commitment       `json:"confirmationStatus"`
+}
+
+// TransactionResult represents a confirmed transaction returned by getTransaction with jsonParsed encoding.
+//
+// Fields:
+// - Slot: the slot the transaction was processed in.
+// - BlockTime: the estimated production time of the block, if available.
+// - Meta: the transaction status metadata.
+// - Transaction: the parsed transaction.
+type TransactionResult struct {
+	Slot        uint64            `json:"slot"`
+	BlockTime   *int64            `json:"blockTime"`
+	Meta        *TransactionMeta  `json:"meta"`
+	Transaction ParsedTransaction `json:"transaction"`
+}
+
+// TransactionMeta represents the status metadata of a confirmed transaction.
+type TransactionMeta struct {
+	Err                  TransactionError    `json:"err"`
+	Fee                  uint64              `json:"fee"`
+	PreBalances          []uint64            `json:"preBalances"`
+	PostBalances         []uint64            `json:"postBalances"`
+	PreTokenBalances     []TokenBalance      `json:"preTokenBalances"`
+	PostTokenBalances    []TokenBalance      `json:"postTokenBalances"`
+	InnerInstructions    []InnerInstructions `json:"innerInstructions"`
+	LogMessages          []string            `json:"logMessages"`
+	ComputeUnitsConsumed *uint64             `json:"computeUnitsConsumed"`
+}
+
+// TokenBalance represents the token balance of an account before or after a transaction.
+type TokenBalance struct {
+	AccountIndex  int         `json:"accountIndex"`
+	Mint          string      `json:"mint"`
+	Owner         string      `json:"owner"`
+	ProgramID     string      `json:"programId"`
+	UITokenAmount TokenAmount `json:"uiTokenAmount"`
+}
+
+// InnerInstructions represents the instructions invoked by a top-level instruction.
+type InnerInstructions struct {
+	Index        int                 `json:"index"`
+	Instructions []ParsedInstruction `json:"instructions"`
+}
+
+// ParsedTransaction represents a transaction in jsonParsed encoding.
+type ParsedTransaction struct {
+	Signatures []string      `json:"signatures"`
+	Message    ParsedMessage `json:"message"`
+}
+
+// ParsedMessage represents a transaction message in jsonParsed encoding.
+type ParsedMessage struct {
+	AccountKeys     []ParsedAccountKey  `json:"accountKeys"`
+	RecentBlockhash string              `json:"recentBlockhash"`
+	Instructions    []ParsedInstruction `json:"instructions"`
+}
+
+// ParsedAccountKey represents an account referenced by a transaction message.
+type ParsedAccountKey struct {
+	Pubkey   string `json:"pubkey"`
+	Signer   bool   `json:"signer"`
+	Writable bool   `json:"writable"`
+	Source   string `json:"source"`
+}
+
+// ParsedInstruction represents an instruction in jsonParsed encoding.
+// Instructions of known programs have Program and Parsed set, others carry raw Accounts and Data.
+type ParsedInstruction struct {
+	Program     string          `json:"program"`
+	ProgramID   string          `json:"programId"`
+	Parsed      json.RawMessage `json:"parsed"`
+	Accounts    []string        `json:"accounts"`
+	Data        string          `json:"data"`
+	StackHeight *int            `json:"stackHeight"`
+}
+
+// AccountInfo represents an account returned by getAccountInfo with jsonParsed encoding.
+//
+// Fields:
+// - Lamports: the balance of the account.
+// - Owner: the program owning the account.
+// - Executable: whether the account holds a program.
+// - Data: the account data, parsed for known programs.
+type AccountInfo struct {
+	Lamports   uint64          `json:"lamports"`
+	Owner      string          `json:"owner"`
+	Executable bool            `json:"executable"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// LatestBlockhash represents the result of getLatestBlockhash.
+//
+// Fields:
+// - Blockhash: the base58 encoded blockhash.
+// - LastValidBlockHeight: the last block height at which the blockhash is accepted.
+type LatestBlockhash struct {
+	Blockhash            string `json:"blockhash"`
+	LastValidBlockHeight uint64 `json:"lastValidBlockHeight"`
+}
+
+// SignatureInfo represents a confirmed transaction signature returned by getSignaturesForAddress.
+//
+// Fields:
+// - Signature: the base58 encoded transaction signature.
+// - Slot: the slot the transaction was processed in.
+// - Err: the error if the transaction failed.
+// - Memo: the memo associated with the transaction, if any.
+// - BlockTime: the estimated production time of the block, if available.
+// - ConfirmationStatus: the cluster confirmation status of the transaction.
+type SignatureInfo struct {
+	Signature          string           `json:"signature"`
+	Slot               uint64           `json:"slot"`
+	Err                TransactionError `json:"err"`
+	Memo               *string          `json:"memo"`
+	BlockTime          *int64           `json:"blockTime"`
+	ConfirmationStatus Commitment       `json:"confirmationStatus"`
+}
+
+// SignaturesForAddressOptions holds the paging options for getSignaturesForAddress.
+// Signatures are returned newest first.
+//
+// Fields:
+// - Before: start searching backwards from this signature, exclusive.
+// - Until: stop searching once this signature is reached, exclusive.
+// - Limit: the maximum number of signatures to return (1-1000).
+type SignaturesForAddressOptions struct {
+	Before string
+	Until  string
+	Limit  int
+}
+
+// Block represents the header data of a confirmed block returned by getBlock.
+//
+// Fields:
+// - Blockhash: the base58 encoded blockhash.
+// - PreviousBlockhash: the blockhash of the parent block.
+// - ParentSlot: the slot of the parent block.
+// - BlockTime: the estimated production time of the block, if available.
+// - BlockHeight: the number of blocks beneath this block, if available.
+type Block struct {
+	Blockhash         string  `json:"blockhash"`
+	PreviousBlockhash string  `json:"previousBlockhash"`
+	ParentSlot        uint64  `json:"parentSlot"`
+	BlockTime         *int64  `json:"blockTime"`
+	BlockHeight       *uint64 `json:"blockHeight"`
+}
+
+// SendOptions holds the options for sendTransaction.
+//
+// Fields:
+// - SkipPreflight: disables the preflight transaction checks.
+// - PreflightCommitment: the commitment level used for preflight.
+// - MaxRetries: the maximum number of times the node retries sending the transaction.
+type SendOptions struct {
+	SkipPreflight       bool
+	PreflightCommitment Commitment
+	MaxRetries          *uint
+}
+
+// SimulateOptions holds the options for simulateTransaction.
+//
+// Fields:
+// - SigVerify: verifies the transaction signatures, conflicts with ReplaceRecentBlockhash.
+// - ReplaceRecentBlockhash: replaces the transaction blockhash with the most recent one.
+// - Commitment: the commitment level the transaction is simulated at.
+type SimulateOptions struct {
+	SigVerify              bool
+	ReplaceRecentBlockhash bool
+	Commitment             Commitment
+}
+
+// SimulationResult represents the result of simulateTransaction.
+//
+// Fields:
+// - Err: the error if the transaction failed.
+// - Logs: the log messages emitted by the transaction.
+// - UnitsConsumed: the number of compute units consumed by the transaction.
+type SimulationResult struct {
+	Err           TransactionError `json:"err"`
+	Logs          []string         `json:"logs"`
+	UnitsConsumed *uint64          `json:"unitsConsumed"`
+}
+
+// PrioritizationFee represents the minimum prioritization fee paid by a transaction landing in a recent slot.
+//
+// Fields:
+// - Slot: the slot the fee was observed in.
+// - PrioritizationFee: the fee in micro-lamports per compute unit.
+type PrioritizationFee struct {
+	Slot              uint64 `json:"slot"`
+	PrioritizationFee uint64 `json:"prioritizationFee"`
+}
+
+// contextResult wraps responses that carry the slot context.
+type contextResult struct {
+	Context struct {
+		Slot uint64 `json:"slot"`
+	} `json:"context"`
+	Value json.RawMessage `json:"value"`
+}
+
+// rawTokenAccount represents a token account in jsonParsed encoding as returned by the node.
+type rawTokenAccount struct {
+	Pubkey  string `json:"pubkey"`
+	Account struct {
+		Owner string `json:"owner"`
+		Data  struct {
+			Parsed struct {
+				Info struct {
+					Mint        string      `json:"mint"`
+					Owner       string      `json:"owner"`
+					TokenAmount TokenAmount `json:"tokenAmount"`
+				} `json:"info"`
+			} `json:"parsed"`
+		} `json:"data"`
+	} `json:"account"`
+}
@@ -0,0 +1,358 @@
+package solclient
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+const (
+	// pingInterval is the interval between keepalive pings sent to the node.
+	pingInterval = 30 * time.Second
+	// pongWait is the time allowed to read the next message or pong from the node.
+	pongWait = 2 * pingInterval
+	// writeWait is the time allowed to write a message to the node.
+	writeWait = 10 * time.Second
+	// notificationBufferSize is the capacity of a subscription's notification channel.
+	notificationBufferSize = 128
+)
+
+// ErrWSClosed is returned when a request is made on, or a subscription is interrupted by, a closed connection.
+var ErrWSClosed = errors.New("websocket connection closed")
+
+// LogsNotification represents a transaction reported by a logsSubscribe subscription.
+//
+// Fields:
+// - Slot: the slot the transaction was processed in.
+// - Signature: the base58 encoded transaction signature.
+// - Err: the error if the transaction failed.
+// - Logs: the log messages emitted by the transaction.
+type LogsNotification struct {
+	Slot      uint64
+	Signature string
+	Err       TransactionError
+	Logs      []string
+}
+
+// WSClient is a Solana PubSub client.
+type WSClient struct {
+	conn       *websocket.Conn
+	writeMutex sync.Mutex
+
+	mutex   sync.Mutex
+	nextID  uint64
+	pending map[uint64]*pendingRequest
+	subs    map[uint64]*LogsSubscription
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// LogsSubscription represents an active logsSubscribe subscription.
+type LogsSubscription struct {
+	client        *WSClient
+	id            uint64
+	notifications chan LogsNotification
+	errChan       chan error
+	done          chan struct{}
+	closeOnce     sync.Once
+}
+
+// pendingRequest tracks a request waiting for its response.
+type pendingRequest struct {
+	response chan wsResponse
+	sub      *LogsSubscription // Subscription registered when the response arrives, if any.
+}
+
+// wsResponse holds the outcome of a request.
+type wsResponse struct {
+	result json.RawMessage
+	err    error
+}
+
+// wsMessage represents any message received from the node: a response or a notification.
+type wsMessage struct {
+	ID     *uint64         `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+	Method string `json:"method"`
+	Params *struct {
+		Subscription uint64          `json:"subscription"`
+		Result       json.RawMessage `json:"result"`
+	} `json:"params"`
+}
+
+// DialWS connects a PubSub client to the given Solana node.
+// HTTP URLs are converted to their WebSocket counterpart.
+//
+// Parameters:
+// - ctx: the context for managing the dial.
+// - rawurl: the URL of the Solana node.
+//
+// Returns:
+// - *WSClient: a new WSClient instance.
+// - error: an error if the endpoint cannot be dialed.
+func DialWS(ctx context.Context, rawurl string) (*WSClient, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, WSEndpoint(rawurl), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to dial solana websocket")
+	}
+
+	c := &WSClient{
+		conn:    conn,
+		pending: make(map[uint64]*pendingRequest),
+		subs:    make(map[uint64]*LogsSubscription),
+		closed:  make(chan struct{}),
+	}
+
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	go c.readLoop()
+	go c.pingLoop()
+
+	return c, nil
+}
+
+// Close closes the connection and interrupts all subscriptions.
+func (c *WSClient) Close() {
+	c.shutdown(ErrWSClosed)
+}
+
+// LogsSubscribe subscribes to transactions mentioning the given address.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - mention: the base58 encoded address the transactions must mention.
+// - commitment: the commitment level.
+//
+// Returns:
+// - *LogsSubscription: the new subscription.
+// - error: an error if the node rejects the subscription.
+func (c *WSClient) LogsSubscribe(ctx context.Context, mention string, commitment Commitment) (*LogsSubscription, error) {
+	sub := &LogsSubscription{
+		client:        c,
+		notifications: make(chan LogsNotification, notificationBufferSize),
+		errChan:       make(chan error, 1),
+		done:          make(chan struct{}),
+	}
+
+	params := []interface{}{
+		map[string]interface{}{"mentions": []string{mention}},
+		commitmentConfig(commitment),
+	}
+
+	if _, err := c.call(ctx, "logsSubscribe", params, sub); err != nil {
+		return nil, errors.Wrap(err, "logsSubscribe failed")
+	}
+
+	return sub, nil
+}
+
+// Notifications returns the channel receiving the transactions reported by the subscription.
+//
+// Returns:
+// - <-chan LogsNotification: the notification channel.
+func (s *LogsSubscription) Notifications() <-chan LogsNotification {
+	return s.notifications
+}
+
+// Err returns the channel receiving the error that interrupted the subscription.
+//
+// Returns:
+// - <-chan error: the error channel.
+func (s *LogsSubscription) Err() <-chan error {
+	return s.errChan
+}
+
+// Unsubscribe cancels the subscription. It is safe to call multiple times.
+func (s *LogsSubscription) Unsubscribe() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+
+		s.client.mutex.Lock()
+		delete(s.client.subs, s.id)
+		s.client.mutex.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), writeWait)
+		defer cancel()
+
+		// Best effort, the subscription is dropped by the node anyway once the connection closes.
+		_, _ = s.client.call(ctx, "logsUnsubscribe", []interface{}{s.id}, nil)
+	})
+}
+
+// call sends a request and waits for its response.
+// When sub is set, it is registered under the returned subscription ID before any notification is dispatched.
+func (c *WSClient) call(ctx context.Context, method string, params []interface{}, sub *LogsSubscription) (json.RawMessage, error) {
+	request := &pendingRequest{
+		response: make(chan wsResponse, 1),
+		sub:      sub,
+	}
+
+	c.mutex.Lock()
+	c.nextID++
+	id := c.nextID
+	c.pending[id] = request
+	c.mutex.Unlock()
+
+	defer func() {
+		c.mutex.Lock()
+		delete(c.pending, id)
+		c.mutex.Unlock()
+	}()
+
+	message := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"method":  method,
+		"params":  params,
+	}
+
+	c.writeMutex.Lock()
+	c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+	err := c.conn.WriteJSON(message)
+	c.writeMutex.Unlock()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to write request")
+	}
+
+	select {
+	case response := <-request.response:
+		return response.result, response.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.closed:
+		return nil, ErrWSClosed
+	}
+}
+
+// readLoop reads messages from the connection and dispatches responses and notifications.
+func (c *WSClient) readLoop() {
+	for {
+		var message wsMessage
+		if err := c.conn.ReadJSON(&message); err != nil {
+			c.shutdown(errors.Wrap(err, "failed to read websocket message"))
+			return
+		}
+
+		if message.ID != nil {
+			c.handleResponse(*message.ID, &message)
+			continue
+		}
+
+		if message.Method == "logsNotification" && message.Params != nil {
+			c.handleNotification(message.Params.Subscription, message.Params.Result)
+		}
+	}
+}
+
+// handleResponse delivers a response to the pending request with the given ID.
+func (c *WSClient) handleResponse(id uint64, message *wsMessage) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	request, ok := c.pending[id]
+	if !ok {
+		return
+	}
+
+	if message.Error != nil {
+		request.response <- wsResponse{err: errors.Errorf("rpc error %d: %s", message.Error.Code, message.Error.Message)}
+		return
+	}
+
+	if request.sub != nil {
+		if err := json.Unmarshal(message.Result, &request.sub.id); err != nil {
+			request.response <- wsResponse{err: errors.Wrap(err, "failed to decode subscription id")}
+			return
+		}
+		c.subs[request.sub.id] = request.sub
+	}
+
+	request.response <- wsResponse{result: message.Result}
+}
+
+// handleNotification decodes a logsNotification and delivers it to the matching subscription.
+func (c *WSClient) handleNotification(subID uint64, payload json.RawMessage) {
+	c.mutex.Lock()
+	sub, ok := c.subs[subID]
+	c.mutex.Unlock()
+	if !ok {
+		return
+	}
+
+	var result contextResult
+	if err := json.Unmarshal(payload, &result); err != nil {
+		return
+	}
+
+	var value struct {
+		Signature string           `json:"signature"`
+		Err       TransactionError `json:"err"`
+		Logs      []string         `json:"logs"`
+	}
+	if err := json.Unmarshal(result.Value, &value); err != nil {
+		return
+	}
+
+	notification := LogsNotification{
+		Slot:      result.Context.Slot,
+		Signature: value.Signature,
+		Err:       value.Err,
+		Logs:      value.Logs,
+	}
+
+	select {
+	case sub.notifications <- notification:
+	case <-sub.done:
+	case <-c.closed:
+	}
+}
+
+// pingLoop keeps the connection alive, the node drops idle connections.
+func (c *WSClient) pingLoop() {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.closed:
+			return
+		case <-ticker.C:
+			c.writeMutex.Lock()
+			err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait))
+			c.writeMutex.Unlock()
+			if err != nil {
+				c.shutdown(errors.Wrap(err, "failed to send ping"))
+				return
+			}
+		}
+	}
+}
+
+// shutdown closes the connection once and reports the error to all active subscriptions.
+func (c *WSClient) shutdown(err error) {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		c.conn.Close()
+
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+
+		for _, sub := range c.subs {
+			select {
+			case sub.errChan <- err:
+			default:
+			}
+		}
+	})
+}
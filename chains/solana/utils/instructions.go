@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"encoding/binary"
+)
+
+const (
+	// systemInstructionTransfer is the System program transfer instruction index.
+	systemInstructionTransfer = 2
+	// tokenInstructionTransferChecked is the SPL Token TransferChecked instruction index.
+	tokenInstructionTransferChecked = 12
+	// associatedTokenInstructionCreateIdempotent is the Associated Token Account CreateIdempotent instruction index.
+	associatedTokenInstructionCreateIdempotent = 1
+	// computeBudgetInstructionSetComputeUnitLimit is the Compute Budget SetComputeUnitLimit instruction index.
+	computeBudgetInstructionSetComputeUnitLimit = 2
+	// computeBudgetInstructionSetComputeUnitPrice is the Compute Budget SetComputeUnitPrice instruction index.
+	computeBudgetInstructionSetComputeUnitPrice = 3
+)
+
+// NewTransferInstruction creates a System program instruction transferring lamports.
+//
+// Parameters:
+// - from: the funding account, which must sign.
+// - to: the recipient account.
+// - lamports: the amount to transfer.
+//
+// Returns:
+// - Instruction: the transfer instruction.
+func NewTransferInstruction(from, to PublicKey, lamports uint64) Instruction {
+	data := make([]byte, 12)
+	binary.LittleEndian.PutUint32(data[0:4], systemInstructionTransfer)
+	binary.LittleEndian.PutUint64(data[4:12], lamports)
+
+	return Instruction{
+		ProgramID: SystemProgramID,
+		Accounts: []AccountMeta{
+			{PublicKey: from, IsSigner: true, IsWritable: true},
+			{PublicKey: to, IsWritable: true},
+		},
+		Data: data,
+	}
+}
+
+// NewTransferCheckedInstruction creates an SPL Token TransferChecked instruction.
+//
+// Parameters:
+// - tokenProgramID: the token program of the mint (Token or Token-2022).
+// - source: the source token account.
+// - mint: the token mint.
+// - destination: the destination token account.
+// - owner: the owner of the source account, which must sign.
+// - amount: the amount in base units.
+// - decimals: the mint decimals.
+//
+// Returns:
+// - Instruction: the transfer instruction.
+func NewTransferCheckedInstruction(tokenProgramID, source, mint, destination, owner PublicKey, amount uint64, decimals uint8) Instruction {
+	data := make([]byte, 10)
+	data[0] = tokenInstructionTransferChecked
+	binary.LittleEndian.PutUint64(data[1:9], amount)
+	data[9] = decimals
+
+	return Instruction{
+		ProgramID: tokenProgramID,
+		Accounts: []AccountMeta{
+			{PublicKey: source, IsWritable: true},
+			{PublicKey: mint},
+			{PublicKey: destination, IsWritable: true},
+			{PublicKey: owner, IsSigner: true},
+		},
+		Data: data,
+	}
+}
+
+// NewCreateAssociatedTokenAccountIdempotentInstruction creates an instruction creating the associated token account
+// of a wallet, which succeeds even if the account already exists.
+//
+// Parameters:
+// - payer: the account paying for the account creation, which must sign.
+// - associatedAccount: the associated token account address.
+// - wallet: the wallet owning the new account.
+// - mint: the token mint.
+// - tokenProgramID: the token program of the mint (Token or Token-2022).
+//
+// Returns:
+// - Instruction: the create instruction.
+func NewCreateAssociatedTokenAccountIdempotentInstruction(payer, associatedAccount, wallet, mint, tokenProgramID PublicKey) Instruction {
+	return Instruction{
+		ProgramID: AssociatedTokenProgramID,
+		Accounts: []AccountMeta{
+			{PublicKey: payer, IsSigner: true, IsWritable: true},
+			{PublicKey: associatedAccount, IsWritable: true},
+			{PublicKey: wallet},
+			{PublicKey: mint},
+			{PublicKey: SystemProgramID},
+			{PublicKey: tokenProgramID},
+		},
+		Data: []byte{associatedTokenInstructionCreateIdempotent},
+	}
+}
+
+// NewSetComputeUnitLimitInstruction creates a Compute Budget instruction setting the transaction compute unit limit.
+//
+// Parameters:
+// - units: the compute unit limit.
+//
+// Returns:
+// - Instruction: the compute budget instruction.
+func NewSetComputeUnitLimitInstruction(units uint32) Instruction {
+	data := make([]byte, 5)
+	data[0] = computeBudgetInstructionSetComputeUnitLimit
+	binary.LittleEndian.PutUint32(data[1:5], units)
+
+	return Instruction{
+		ProgramID: ComputeBudgetProgramID,
+		Data:      data,
+	}
+}
+
+// NewSetComputeUnitPriceInstruction creates a Compute Budget instruction setting the priority fee.
+//
+// Parameters:
+// - microLamports: the price per compute unit in micro-lamports.
+//
+// Returns:
+// - Instruction: the compute budget instruction.
+func NewSetComputeUnitPriceInstruction(microLamports uint64) Instruction {
+	data := make([]byte, 9)
+	data[0] = computeBudgetInstructionSetComputeUnitPrice
+	binary.LittleEndian.PutUint64(data[1:9], microLamports)
+
+	return Instruction{
+		ProgramID: ComputeBudgetProgramID,
+		Data:      data,
+	}
+}
+
+// NewMemoInstruction creates an SPL Memo instruction.
+//
+// Parameters:
+// - memo: the memo data, which must be valid UTF-8.
+// - signers: the accounts that must sign the memo, if any.
+//
+// Returns:
+// - Instruction: the memo instruction.
+func NewMemoInstruction(memo []byte, signers ...PublicKey) Instruction {
+	accounts := make([]AccountMeta, 0, len(signers))
+	for _, signer := range signers {
+		accounts = append(accounts, AccountMeta{PublicKey: signer, IsSigner: true})
+	}
+
+	return Instruction{
+		ProgramID: MemoProgramID,
+		Accounts:  accounts,
+		Data:      memo,
+	}
+}
@@ -0,0 +1,241 @@
+package utils
+
+import (
+	"encoding/json"
+	"github.com/ClipFinance/relay-lib/chains/solana/solclient"
+	"github.com/mr-tron/base58"
+	"strconv"
+	"strings"
+)
+
+// Transfer represents a SOL or SPL token transfer performed by a transaction.
+//
+// Fields:
+// - EventType: the type of the transfer (NativeTransfer or TokenTransfer).
+// - InstructionIndex: the index of the top-level instruction, inner instructions share the index of their parent.
+// - Source: the debited account, a wallet for SOL and a token account for SPL tokens.
+// - Destination: the credited account, a wallet for SOL and a token account for SPL tokens.
+// - DestinationOwner: the wallet owning the destination account.
+// - Authority: the wallet that authorized the transfer.
+// - Mint: the token mint, ZeroAddress for native SOL.
+// - Amount: the amount in base units.
+type Transfer struct {
+	EventType        string
+	InstructionIndex int
+	Source           string
+	Destination      string
+	DestinationOwner string
+	Authority        string
+	Mint             string
+	Amount           string
+}
+
+// parsedInstruction represents the parsed payload of a System or SPL Token instruction.
+type parsedInstruction struct {
+	Type string          `json:"type"`
+	Info json.RawMessage `json:"info"`
+}
+
+// parsedTransferInfo holds the fields of the System and SPL Token transfer instructions.
+type parsedTransferInfo struct {
+	Source            string                 `json:"source"`
+	Destination       string                 `json:"destination"`
+	Lamports          uint64                 `json:"lamports"`
+	Amount            string                 `json:"amount"`
+	Authority         string                 `json:"authority"`
+	MultisigAuthority string                 `json:"multisigAuthority"`
+	Mint              string                 `json:"mint"`
+	TokenAmount       *solclient.TokenAmount `json:"tokenAmount"`
+}
+
+// ParseTransfers extracts the SOL and SPL token transfers performed by a jsonParsed transaction,
+// including transfers made by inner instructions. Transfers are returned in execution order.
+//
+// Parameters:
+// - tx: the transaction returned by getTransaction.
+//
+// Returns:
+// - []Transfer: the transfers of the transaction.
+func ParseTransfers(tx *solclient.TransactionResult) []Transfer {
+	tokenBalances := tokenBalancesByAccount(tx)
+
+	innerByIndex := make(map[int][]solclient.ParsedInstruction)
+	if tx.Meta != nil {
+		for _, inner := range tx.Meta.InnerInstructions {
+			innerByIndex[inner.Index] = append(innerByIndex[inner.Index], inner.Instructions...)
+		}
+	}
+
+	var transfers []Transfer
+	for i, instruction := range tx.Transaction.Message.Instructions {
+		if transfer, ok := parseTransfer(instruction, i, tokenBalances); ok {
+			transfers = append(transfers, transfer)
+		}
+		for _, inner := range innerByIndex[i] {
+			if transfer, ok := parseTransfer(inner, i, tokenBalances); ok {
+				transfers = append(transfers, transfer)
+			}
+		}
+	}
+
+	return transfers
+}
+
+// ExtractMemo returns the text of the first SPL Memo instruction of a jsonParsed transaction.
+//
+// Parameters:
+// - tx: the transaction returned by getTransaction.
+//
+// Returns:
+// - string: the trimmed memo text.
+// - bool: false if the transaction has no non-empty memo.
+func ExtractMemo(tx *solclient.TransactionResult) (string, bool) {
+	instructions := append([]solclient.ParsedInstruction{}, tx.Transaction.Message.Instructions...)
+	if tx.Meta != nil {
+		for _, inner := range tx.Meta.InnerInstructions {
+			instructions = append(instructions, inner.Instructions...)
+		}
+	}
+
+	for _, instruction := range instructions {
+		if instruction.ProgramID != MemoProgramID.String() && instruction.ProgramID != MemoV1ProgramID.String() {
+			continue
+		}
+
+		var memo string
+		if len(instruction.Parsed) > 0 {
+			if err := json.Unmarshal(instruction.Parsed, &memo); err != nil {
+				continue
+			}
+		} else {
+			data, err := base58.Decode(instruction.Data)
+			if err != nil {
+				continue
+			}
+			memo = string(data)
+		}
+
+		if memo = strings.TrimSpace(memo); memo != "" {
+			return memo, true
+		}
+	}
+
+	return "", false
+}
+
+// IsSigner reports whether the address signed the jsonParsed transaction.
+//
+// Parameters:
+// - tx: the transaction returned by getTransaction.
+// - address: the base58 encoded address.
+//
+// Returns:
+// - bool: true if the address is a signer of the transaction.
+func IsSigner(tx *solclient.TransactionResult, address string) bool {
+	for _, key := range tx.Transaction.Message.AccountKeys {
+		if key.Pubkey == address && key.Signer {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTransfer converts a System or SPL Token transfer instruction into a Transfer.
+func parseTransfer(instruction solclient.ParsedInstruction, index int, tokenBalances map[string]solclient.TokenBalance) (Transfer, bool) {
+	if len(instruction.Parsed) == 0 {
+		return Transfer{}, false
+	}
+
+	var parsed parsedInstruction
+	if err := json.Unmarshal(instruction.Parsed, &parsed); err != nil {
+		return Transfer{}, false
+	}
+
+	var info parsedTransferInfo
+	if err := json.Unmarshal(parsed.Info, &info); err != nil {
+		return Transfer{}, false
+	}
+
+	switch instruction.ProgramID {
+	case SystemProgramID.String():
+		if parsed.Type != "transfer" && parsed.Type != "transferWithSeed" {
+			return Transfer{}, false
+		}
+
+		return Transfer{
+			EventType:        EventTypeNativeTransfer,
+			InstructionIndex: index,
+			Source:           info.Source,
+			Destination:      info.Destination,
+			DestinationOwner: info.Destination,
+			Authority:        info.Source,
+			Mint:             ZeroAddress,
+			Amount:           strconv.FormatUint(info.Lamports, 10),
+		}, true
+
+	case TokenProgramID.String(), Token2022ProgramID.String():
+		amount := info.Amount
+		switch parsed.Type {
+		case "transfer":
+		case "transferChecked":
+			if info.TokenAmount == nil {
+				return Transfer{}, false
+			}
+			amount = info.TokenAmount.Amount
+		default:
+			return Transfer{}, false
+		}
+
+		authority := info.Authority
+		if authority == "" {
+			authority = info.MultisigAuthority
+		}
+
+		destinationBalance := tokenBalances[info.Destination]
+
+		mint := info.Mint
+		if mint == "" {
+			mint = destinationBalance.Mint
+		}
+		if mint == "" {
+			mint = tokenBalances[info.Source].Mint
+		}
+
+		return Transfer{
+			EventType:        EventTypeTokenTransfer,
+			InstructionIndex: index,
+			Source:           info.Source,
+			Destination:      info.Destination,
+			DestinationOwner: destinationBalance.Owner,
+			Authority:        authority,
+			Mint:             mint,
+			Amount:           amount,
+		}, true
+	}
+
+	return Transfer{}, false
+}
+
+// tokenBalancesByAccount maps the token accounts of a transaction to their balance entries.
+// Post-transaction balances take precedence, pre-transaction balances cover accounts closed by the transaction.
+func tokenBalancesByAccount(tx *solclient.TransactionResult) map[string]solclient.TokenBalance {
+	balances := make(map[string]solclient.TokenBalance)
+	if tx.Meta == nil {
+		return balances
+	}
+
+	accountKeys := tx.Transaction.Message.AccountKeys
+	add := func(tokenBalances []solclient.TokenBalance) {
+		for _, balance := range tokenBalances {
+			if balance.AccountIndex < 0 || balance.AccountIndex >= len(accountKeys) {
+				continue
+			}
+			balances[accountKeys[balance.AccountIndex].Pubkey] = balance
+		}
+	}
+
+	add(tx.Meta.PreTokenBalances)
+	add(tx.Meta.PostTokenBalances)
+
+	return balances
+}
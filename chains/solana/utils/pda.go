@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"filippo.io/edwards25519"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxSeeds is the maximum number of seeds of a program derived address.
+	maxSeeds = 16
+	// maxSeedLength is the maximum length of a single seed in bytes.
+	maxSeedLength = 32
+	// pdaMarker is appended to the seeds when hashing a program derived address.
+	pdaMarker = "ProgramDerivedAddress"
+)
+
+// CreateProgramAddress derives a program address from the seeds and program ID.
+// It fails if the derived address lies on the Ed25519 curve.
+//
+// Parameters:
+// - seeds: the seeds of the address, including the bump seed.
+// - programID: the program owning the address.
+//
+// Returns:
+// - PublicKey: the derived address.
+// - error: an error if the seeds are invalid or the address is on the curve.
+func CreateProgramAddress(seeds [][]byte, programID PublicKey) (PublicKey, error) {
+	if len(seeds) > maxSeeds {
+		return PublicKey{}, errors.New("max seed count exceeded")
+	}
+
+	hasher := sha256.New()
+	for _, seed := range seeds {
+		if len(seed) > maxSeedLength {
+			return PublicKey{}, errors.New("max seed length exceeded")
+		}
+		hasher.Write(seed)
+	}
+	hasher.Write(programID[:])
+	hasher.Write([]byte(pdaMarker))
+
+	var address PublicKey
+	copy(address[:], hasher.Sum(nil))
+
+	if isOnCurve(address) {
+		return PublicKey{}, errors.New("derived address is on the curve")
+	}
+
+	return address, nil
+}
+
+// FindProgramAddress finds a valid program derived address and its bump seed.
+//
+// Parameters:
+// - seeds: the seeds of the address, without the bump seed.
+// - programID: the program owning the address.
+//
+// Returns:
+// - PublicKey: the derived address.
+// - uint8: the bump seed.
+// - error: an error if no valid bump seed exists.
+func FindProgramAddress(seeds [][]byte, programID PublicKey) (PublicKey, uint8, error) {
+	for bump := 255; bump >= 0; bump-- {
+		seedsWithBump := append(append([][]byte{}, seeds...), []byte{byte(bump)})
+		address, err := CreateProgramAddress(seedsWithBump, programID)
+		if err == nil {
+			return address, uint8(bump), nil
+		}
+	}
+
+	return PublicKey{}, 0, errors.New("unable to find a viable program address bump seed")
+}
+
+// FindAssociatedTokenAddress derives the associated token account of a wallet for the given mint.
+//
+// Parameters:
+// - wallet: the wallet owning the token account.
+// - mint: the token mint.
+// - tokenProgramID: the token program of the mint (Token or Token-2022).
+//
+// Returns:
+// - PublicKey: the associated token account address.
+// - error: an error if the address cannot be derived.
+func FindAssociatedTokenAddress(wallet, mint, tokenProgramID PublicKey) (PublicKey, error) {
+	address, _, err := FindProgramAddress([][]byte{wallet[:], tokenProgramID[:], mint[:]}, AssociatedTokenProgramID)
+	return address, err
+}
+
+// isOnCurve reports whether the bytes are a valid compressed Ed25519 point.
+func isOnCurve(key PublicKey) bool {
+	_, err := new(edwards25519.Point).SetBytes(key[:])
+	return err == nil
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+)
+
+const (
+	// PublicKeyLength is the length of a Solana public key in bytes.
+	PublicKeyLength = 32
+	// SignatureLength is the length of an Ed25519 signature in bytes.
+	SignatureLength = 64
+)
+
+// PublicKey represents a Solana account address.
+type PublicKey [PublicKeyLength]byte
+
+// PublicKeyFromBase58 decodes a base58 encoded Solana address.
+//
+// Parameters:
+// - s: the base58 encoded address.
+//
+// Returns:
+// - PublicKey: the decoded public key.
+// - error: an error if the address is not valid base58 or has the wrong length.
+func PublicKeyFromBase58(s string) (PublicKey, error) {
+	var key PublicKey
+
+	decoded, err := base58.Decode(s)
+	if err != nil {
+		return key, errors.Wrap(err, "failed to decode base58 public key")
+	}
+
+	if len(decoded) != PublicKeyLength {
+		return key, errors.Errorf("invalid public key length, expected: %d, got: %d", PublicKeyLength, len(decoded))
+	}
+
+	copy(key[:], decoded)
+	return key, nil
+}
+
+// MustPublicKeyFromBase58 decodes a base58 encoded Solana address and panics on failure.
+// It is intended for well-known program addresses.
+func MustPublicKeyFromBase58(s string) PublicKey {
+	key, err := PublicKeyFromBase58(s)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// String returns the base58 representation of the public key.
+func (p PublicKey) String() string {
+	return base58.Encode(p[:])
+}
+
+// Bytes returns the public key as a byte slice.
+func (p PublicKey) Bytes() []byte {
+	return p[:]
+}
+
+// Signature represents an Ed25519 transaction signature.
+type Signature [SignatureLength]byte
+
+// String returns the base58 representation of the signature.
+func (s Signature) String() string {
+	return base58.Encode(s[:])
+}
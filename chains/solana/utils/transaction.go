@@ -0,0 +1,233 @@
+package utils
+
+import (
+	"bytes"
+	"github.com/pkg/errors"
+)
+
+// AccountMeta describes an account referenced by an instruction.
+//
+// Fields:
+// - PublicKey: the account address.
+// - IsSigner: whether the account must sign the transaction.
+// - IsWritable: whether the instruction may modify the account.
+type AccountMeta struct {
+	PublicKey  PublicKey
+	IsSigner   bool
+	IsWritable bool
+}
+
+// Instruction represents a single program invocation.
+//
+// Fields:
+// - ProgramID: the program to invoke.
+// - Accounts: the accounts passed to the program.
+// - Data: the instruction data.
+type Instruction struct {
+	ProgramID PublicKey
+	Accounts  []AccountMeta
+	Data      []byte
+}
+
+// MessageHeader describes the signer and read-only sections of the message account keys.
+type MessageHeader struct {
+	NumRequiredSignatures       uint8
+	NumReadonlySignedAccounts   uint8
+	NumReadonlyUnsignedAccounts uint8
+}
+
+// CompiledInstruction is an instruction referencing accounts by their index in the message.
+type CompiledInstruction struct {
+	ProgramIDIndex uint8
+	Accounts       []uint8
+	Data           []byte
+}
+
+// Message represents a legacy Solana transaction message.
+type Message struct {
+	Header          MessageHeader
+	AccountKeys     []PublicKey
+	RecentBlockhash PublicKey
+	Instructions    []CompiledInstruction
+}
+
+// Transaction represents a legacy Solana transaction.
+//
+// Fields:
+// - Signatures: the signatures, one per required signer in account key order.
+// - Message: the message covered by the signatures.
+type Transaction struct {
+	Signatures []Signature
+	Message    Message
+}
+
+// NewTransaction compiles the instructions into a legacy transaction paid by feePayer.
+// The returned transaction has zeroed signatures which must be filled by the signers.
+//
+// Parameters:
+// - instructions: the instructions to include.
+// - recentBlockhash: the base58 encoded recent blockhash.
+// - feePayer: the account paying the transaction fee.
+//
+// Returns:
+// - *Transaction: the unsigned transaction.
+// - error: an error if the blockhash is invalid or the message is too large.
+func NewTransaction(instructions []Instruction, recentBlockhash string, feePayer PublicKey) (*Transaction, error) {
+	if len(instructions) == 0 {
+		return nil, errors.New("transaction requires at least one instruction")
+	}
+
+	blockhash, err := PublicKeyFromBase58(recentBlockhash)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid recent blockhash")
+	}
+
+	// Collect the accounts in order of appearance, merging signer and writable flags.
+	metas := []AccountMeta{{PublicKey: feePayer, IsSigner: true, IsWritable: true}}
+	indexOf := map[PublicKey]int{feePayer: 0}
+	addAccount := func(meta AccountMeta) {
+		if idx, ok := indexOf[meta.PublicKey]; ok {
+			metas[idx].IsSigner = metas[idx].IsSigner || meta.IsSigner
+			metas[idx].IsWritable = metas[idx].IsWritable || meta.IsWritable
+			return
+		}
+		indexOf[meta.PublicKey] = len(metas)
+		metas = append(metas, meta)
+	}
+
+	for _, instruction := range instructions {
+		for _, account := range instruction.Accounts {
+			addAccount(account)
+		}
+		addAccount(AccountMeta{PublicKey: instruction.ProgramID})
+	}
+
+	// Order accounts: writable signers, read-only signers, writable non-signers, read-only non-signers.
+	var ordered []AccountMeta
+	for _, group := range []struct{ signer, writable bool }{{true, true}, {true, false}, {false, true}, {false, false}} {
+		for _, meta := range metas {
+			if meta.IsSigner == group.signer && meta.IsWritable == group.writable {
+				ordered = append(ordered, meta)
+			}
+		}
+	}
+
+	if len(ordered) > 256 {
+		return nil, errors.New("too many accounts in transaction")
+	}
+
+	message := Message{RecentBlockhash: blockhash}
+	keyIndex := make(map[PublicKey]uint8, len(ordered))
+	for i, meta := range ordered {
+		keyIndex[meta.PublicKey] = uint8(i)
+		message.AccountKeys = append(message.AccountKeys, meta.PublicKey)
+
+		switch {
+		case meta.IsSigner:
+			message.Header.NumRequiredSignatures++
+			if !meta.IsWritable {
+				message.Header.NumReadonlySignedAccounts++
+			}
+		case !meta.IsWritable:
+			message.Header.NumReadonlyUnsignedAccounts++
+		}
+	}
+
+	for _, instruction := range instructions {
+		compiled := CompiledInstruction{
+			ProgramIDIndex: keyIndex[instruction.ProgramID],
+			Data:           instruction.Data,
+		}
+		for _, account := range instruction.Accounts {
+			compiled.Accounts = append(compiled.Accounts, keyIndex[account.PublicKey])
+		}
+		message.Instructions = append(message.Instructions, compiled)
+	}
+
+	return &Transaction{
+		Signatures: make([]Signature, message.Header.NumRequiredSignatures),
+		Message:    message,
+	}, nil
+}
+
+// Serialize encodes the message in the Solana wire format. These are the bytes covered by the signatures.
+//
+// Returns:
+// - []byte: the serialized message.
+func (m *Message) Serialize() []byte {
+	var buf bytes.Buffer
+
+	buf.WriteByte(m.Header.NumRequiredSignatures)
+	buf.WriteByte(m.Header.NumReadonlySignedAccounts)
+	buf.WriteByte(m.Header.NumReadonlyUnsignedAccounts)
+
+	writeCompactU16(&buf, len(m.AccountKeys))
+	for _, key := range m.AccountKeys {
+		buf.Write(key[:])
+	}
+
+	buf.Write(m.RecentBlockhash[:])
+
+	writeCompactU16(&buf, len(m.Instructions))
+	for _, instruction := range m.Instructions {
+		buf.WriteByte(instruction.ProgramIDIndex)
+		writeCompactU16(&buf, len(instruction.Accounts))
+		buf.Write(instruction.Accounts)
+		writeCompactU16(&buf, len(instruction.Data))
+		buf.Write(instruction.Data)
+	}
+
+	return buf.Bytes()
+}
+
+// Signers returns the accounts that must sign the transaction, in signature order.
+//
+// Returns:
+// - []PublicKey: the required signers.
+func (t *Transaction) Signers() []PublicKey {
+	return t.Message.AccountKeys[:t.Message.Header.NumRequiredSignatures]
+}
+
+// Signature returns the fee payer signature, which identifies the transaction on chain.
+//
+// Returns:
+// - Signature: the first transaction signature.
+func (t *Transaction) Signature() Signature {
+	if len(t.Signatures) == 0 {
+		return Signature{}
+	}
+	return t.Signatures[0]
+}
+
+// Serialize encodes the signed transaction in the Solana wire format.
+//
+// Returns:
+// - []byte: the serialized transaction.
+// - error: an error if the number of signatures does not match the message header.
+func (t *Transaction) Serialize() ([]byte, error) {
+	if len(t.Signatures) != int(t.Message.Header.NumRequiredSignatures) {
+		return nil, errors.Errorf("signature count mismatch, expected: %d, got: %d", t.Message.Header.NumRequiredSignatures, len(t.Signatures))
+	}
+
+	var buf bytes.Buffer
+	writeCompactU16(&buf, len(t.Signatures))
+	for _, signature := range t.Signatures {
+		buf.Write(signature[:])
+	}
+	buf.Write(t.Message.Serialize())
+
+	return buf.Bytes(), nil
+}
+
+// writeCompactU16 writes a length using the Solana compact-u16 encoding.
+func writeCompactU16(buf *bytes.Buffer, value int) {
+	for {
+		b := byte(value & 0x7f)
+		value >>= 7
+		if value == 0 {
+			buf.WriteByte(b)
+			return
+		}
+		buf.WriteByte(b | 0x80)
+	}
+}
@@ -0,0 +1,69 @@
+package utils
+
+const (
+	// ZeroAddress represents the all-zero public key, used as the native SOL token marker.
+	ZeroAddress = "11111111111111111111111111111111"
+
+	// EventTypeNativeTransfer is the event type of a native SOL deposit.
+	EventTypeNativeTransfer = "NativeTransfer"
+	// EventTypeTokenTransfer is the event type of an SPL token deposit.
+	EventTypeTokenTransfer = "TokenTransfer"
+)
+
+var (
+	// SystemProgramID is the address of the System program.
+	SystemProgramID = MustPublicKeyFromBase58("11111111111111111111111111111111")
+	// TokenProgramID is the address of the SPL Token program.
+	TokenProgramID = MustPublicKeyFromBase58("TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA")
+	// Token2022ProgramID is the address of the SPL Token-2022 program.
+	Token2022ProgramID = MustPublicKeyFromBase58("TokenzQdBNbLqP5VEhdkAS6EPFLC1PHnBqCXEpPxuEb")
+	// AssociatedTokenProgramID is the address of the SPL Associated Token Account program.
+	AssociatedTokenProgramID = MustPublicKeyFromBase58("ATokenGPvbdGVxr1b2hvZbsiqW5xWH25efTNsLJA8knL")
+	// MemoProgramID is the address of the SPL Memo program.
+	MemoProgramID = MustPublicKeyFromBase58("MemoSq4gqABAXKb96qnH8TysNcWxMyWCqXgDLGmfcHr")
+	// MemoV1ProgramID is the address of the legacy SPL Memo program.
+	MemoV1ProgramID = MustPublicKeyFromBase58("Memo1UhkJRfHyvLMcVucJwxXeuD728EqVDDwQDxFMNo")
+	// ComputeBudgetProgramID is the address of the Compute Budget program.
+	ComputeBudgetProgramID = MustPublicKeyFromBase58("ComputeBudget111111111111111111111111111111")
+)
+
+// IsNativeToken reports whether the token address refers to native SOL.
+//
+// Parameters:
+// - tokenAddress: the token address.
+//
+// Returns:
+// - bool: true for an empty address or ZeroAddress.
+func IsNativeToken(tokenAddress string) bool {
+	return tokenAddress == "" || tokenAddress == ZeroAddress
+}
+
+// SolanaTxMetadata represents the metadata of a transaction sent by the solver.
+//
+// Fields:
+// - RecentBlockhash: the blockhash the transaction was built with.
+// - LastValidBlockHeight: the last block height at which the blockhash is accepted.
+// - ComputeUnitLimit: the compute unit limit requested by the transaction.
+// - ComputeUnitPrice: the priority fee paid per compute unit in micro-lamports.
+type SolanaTxMetadata struct {
+	RecentBlockhash      string
+	LastValidBlockHeight uint64
+	ComputeUnitLimit     uint32
+	ComputeUnitPrice     uint64
+}
+
+// SolanaMetadata represents the Solana specific data of a deposit event.
+//
+// Fields:
+// - EventType: the type of the deposit (NativeTransfer or TokenTransfer).
+// - InstructionIndex: the index of the top-level instruction performing the transfer.
+// - Mint: the token mint, ZeroAddress for native SOL.
+// - SourceAccount: the account the funds were debited from (wallet or token account).
+// - DestinationAccount: the account the funds were credited to (wallet or token account).
+type SolanaMetadata struct {
+	EventType          string
+	InstructionIndex   int
+	Mint               string
+	SourceAccount      string
+	DestinationAccount string
+}
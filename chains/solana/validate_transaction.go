@@ -2,12 +2,126 @@ package solana
 
 import (
 	"context"
+	"github.com/ClipFinance/relay-lib/chains/solana/solclient"
+	"github.com/ClipFinance/relay-lib/chains/solana/utils"
 	"github.com/ClipFinance/relay-lib/common/types"
 	"github.com/pkg/errors"
+	"math/big"
 )
 
-// ValidateTransaction validates a transaction based on the quote and the event
+// ValidateTransaction validates a transaction based on the quote and the event.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - quote: the quote containing transaction details.
+// - event: the event containing chain event details.
+//
+// Returns:
+// - error: an error if the transaction validation fails.
 func (s *solana) ValidateTransaction(ctx context.Context, quote *types.Quote, event types.ChainEvent) error {
-	// TODO: need to implement this function
-	return errors.New("not implemented")
+	s.clientMutex.RLock()
+	client := s.client
+	s.clientMutex.RUnlock()
+
+	if client == nil {
+		return errors.New("client not initialized")
+	}
+
+	tx, err := client.GetTransaction(ctx, event.TransactionHash, solclient.CommitmentConfirmed)
+	if err != nil {
+		return errors.Wrap(err, "failed to get transaction details")
+	}
+
+	if err := s.validateTransaction(quote, tx, event, s.SolverAddress()); err != nil {
+		return errors.Wrap(err, "transaction validation failed")
+	}
+
+	return nil
+}
+
+// validateTransaction validates the transaction details against the quote parameters.
+// The transfer reported by the event is validated, or any transfer to the solver if the event carries no metadata.
+func (s *solana) validateTransaction(quote *types.Quote, tx *solclient.TransactionResult, event types.ChainEvent, solverAddr string) error {
+	if tx.Meta == nil {
+		return errors.New("transaction metadata not available")
+	}
+
+	if tx.Meta.Err.IsSet() {
+		return errors.Errorf("transaction failed: %s", tx.Meta.Err.String())
+	}
+
+	memo, ok := utils.ExtractMemo(tx)
+	if !ok {
+		return errors.New("memo not found")
+	}
+
+	// Validate quote ID match
+	if memo != quote.QuoteID {
+		return errors.New("quote ID mismatch")
+	}
+
+	// Validate sender signed the transaction
+	if !utils.IsSigner(tx, quote.Parameters.UserAddress) {
+		return errors.New("sender is not a signer of the transaction")
+	}
+
+	metadata, hasMetadata := event.Metadata.(utils.SolanaMetadata)
+
+	var transfers []utils.Transfer
+	for _, transfer := range utils.ParseTransfers(tx) {
+		if transfer.DestinationOwner != solverAddr {
+			continue
+		}
+		if hasMetadata && (transfer.InstructionIndex != metadata.InstructionIndex || transfer.Destination != metadata.DestinationAccount) {
+			continue
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	if len(transfers) == 0 {
+		return errors.New("receiver address mismatch")
+	}
+
+	var err error
+	for _, transfer := range transfers {
+		if err = validateTransfer(quote, transfer); err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// validateTransfer validates the token, sender and amount of a transfer to the solver.
+func validateTransfer(quote *types.Quote, transfer utils.Transfer) error {
+	// Validate token match
+	if utils.IsNativeToken(quote.Parameters.FromToken) {
+		if transfer.EventType != utils.EventTypeNativeTransfer {
+			return errors.New("token address mismatch")
+		}
+	} else if transfer.EventType != utils.EventTypeTokenTransfer || transfer.Mint != quote.Parameters.FromToken {
+		return errors.New("token address mismatch")
+	}
+
+	// Validate sender address match
+	if transfer.Authority != quote.Parameters.UserAddress {
+		return errors.New("sender address mismatch")
+	}
+
+	// Validate exact amount match
+	amount, ok := new(big.Int).SetString(quote.Parameters.Amount, 10)
+	if !ok {
+		return errors.New("failed to parse quote amount")
+	}
+
+	transferAmount, ok := new(big.Int).SetString(transfer.Amount, 10)
+	if !ok {
+		return errors.New("failed to parse transfer amount")
+	}
+
+	if transferAmount.Cmp(amount) != 0 {
+		return errors.New("transfer amount mismatch")
+	}
+
+	return nil
 }
@@ -2,10 +2,161 @@ package solana
 
 import (
 	"context"
+	"github.com/ClipFinance/relay-lib/chains/solana/solclient"
+	"github.com/ClipFinance/relay-lib/chains/solana/utils"
 	"github.com/ClipFinance/relay-lib/common/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"time"
 )
 
+const (
+	// confirmationPollInterval is the interval between signature status checks.
+	confirmationPollInterval = time.Second
+	// finalizedConfirmations is the number of confirmations after which a block is treated as finalized.
+	finalizedConfirmations = 32
+)
+
+// commitmentLevels orders the commitment levels from the weakest to the strongest.
+var commitmentLevels = map[solclient.Commitment]int{
+	solclient.CommitmentProcessed: 0,
+	solclient.CommitmentConfirmed: 1,
+	solclient.CommitmentFinalized: 2,
+}
+
+// WaitTransactionConfirmation waits for the transaction to reach the commitment level derived from WaitNBlocks:
+// 0 waits for processed, up to 31 for confirmed and 32 or more for finalized.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - tx: the transaction to wait for confirmation.
+//
+// Returns:
+// - types.TransactionStatus: TxDone once the transaction reached the commitment, TxFailed if it landed with an error,
+// or TxNeedsRetry if its blockhash expired before it landed.
+// - error: an error if the client is not initialized, if the transaction failed, or if the context is done.
 func (s *solana) WaitTransactionConfirmation(ctx context.Context, tx *types.Transaction) (types.TransactionStatus, error) {
-	// TODO: Implement transaction confirmation waiting for Solana
-	return types.TxFailed, nil
+	s.clientMutex.RLock()
+	client := s.client
+	s.clientMutex.RUnlock()
+
+	if client == nil {
+		return types.TxNeedsRetry, errors.New("client not initialized")
+	}
+
+	metadata, hasMetadata := txMetadata(tx)
+	commitment := s.confirmationCommitment()
+
+	ticker := time.NewTicker(confirmationPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.WithField("txHash", tx.Hash).Error("WaitTransactionConfirmation: context done")
+			return types.TxFailed, ctx.Err()
+
+		case <-ticker.C:
+			status, err := s.getSignatureStatus(ctx, client, tx.Hash, false)
+			if err != nil {
+				s.logger.WithFields(logrus.Fields{
+					"chain":  s.config.Name,
+					"txHash": tx.Hash,
+				}).WithError(err).Warn("Failed to get signature status")
+				continue
+			}
+
+			if status != nil {
+				if status.Err.IsSet() {
+					return types.TxFailed, errors.Errorf("transaction failed: %s", status.Err.String())
+				}
+				if commitmentLevels[status.ConfirmationStatus] >= commitmentLevels[commitment] {
+					return types.TxDone, nil
+				}
+				continue
+			}
+
+			if !hasMetadata {
+				continue
+			}
+
+			expired, err := s.isBlockhashExpired(ctx, client, tx.Hash, metadata.LastValidBlockHeight)
+			if err != nil {
+				s.logger.WithFields(logrus.Fields{
+					"chain":  s.config.Name,
+					"txHash": tx.Hash,
+				}).WithError(err).Warn("Failed to check blockhash expiry")
+				continue
+			}
+
+			if expired {
+				s.logger.WithFields(logrus.Fields{
+					"chain":                s.config.Name,
+					"txHash":               tx.Hash,
+					"lastValidBlockHeight": metadata.LastValidBlockHeight,
+				}).Warn("Transaction blockhash expired before the transaction landed")
+				return types.TxNeedsRetry, nil
+			}
+		}
+	}
+}
+
+// confirmationCommitment maps ChainConfig.WaitNBlocks to the commitment level to wait for.
+func (s *solana) confirmationCommitment() solclient.Commitment {
+	switch {
+	case s.config.WaitNBlocks == 0:
+		return solclient.CommitmentProcessed
+	case s.config.WaitNBlocks < finalizedConfirmations:
+		return solclient.CommitmentConfirmed
+	default:
+		return solclient.CommitmentFinalized
+	}
+}
+
+// getSignatureStatus returns the status of a single signature, or nil if the cluster does not know it.
+func (s *solana) getSignatureStatus(ctx context.Context, client *solclient.Client, signature string, searchTransactionHistory bool) (*solclient.SignatureStatus, error) {
+	statuses, err := client.GetSignatureStatuses(ctx, []string{signature}, searchTransactionHistory)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(statuses) == 0 {
+		return nil, nil
+	}
+
+	return statuses[0], nil
+}
+
+// isBlockhashExpired reports whether the confirmed block height passed the last valid block height of the transaction
+// while the transaction is still unknown, searching the full history to rule out a late status.
+func (s *solana) isBlockhashExpired(ctx context.Context, client *solclient.Client, signature string, lastValidBlockHeight uint64) (bool, error) {
+	blockHeight, err := client.GetBlockHeight(ctx, solclient.CommitmentConfirmed)
+	if err != nil {
+		return false, err
+	}
+
+	if blockHeight <= lastValidBlockHeight {
+		return false, nil
+	}
+
+	status, err := s.getSignatureStatus(ctx, client, signature, true)
+	if err != nil {
+		return false, err
+	}
+
+	return status == nil, nil
+}
+
+// txMetadata extracts the Solana metadata attached to the transaction by SendAsset.
+func txMetadata(tx *types.Transaction) (utils.SolanaTxMetadata, bool) {
+	switch metadata := tx.Metadata.(type) {
+	case utils.SolanaTxMetadata:
+		return metadata, true
+	case *utils.SolanaTxMetadata:
+		if metadata != nil {
+			return *metadata, true
+		}
+	}
+
+	return utils.SolanaTxMetadata{}, false
 }
@@ -0,0 +1,45 @@
+package checkpoint
+
+import (
+	"context"
+	"github.com/ClipFinance/relay-lib/common/types"
+	"sync"
+)
+
+// memoryStore is an in-memory implementation of the types.CheckpointStore interface.
+// Checkpoints are lost when the process exits.
+type memoryStore struct {
+	checkpoints map[uint64]map[string]string
+	mutex       sync.RWMutex
+}
+
+// NewMemoryStore creates a new in-memory checkpoint store.
+//
+// Returns:
+// - types.CheckpointStore: the new checkpoint store.
+func NewMemoryStore() types.CheckpointStore {
+	return &memoryStore{
+		checkpoints: make(map[uint64]map[string]string),
+	}
+}
+
+// GetCheckpoint returns the stored checkpoint value, or an empty string if no checkpoint exists.
+func (s *memoryStore) GetCheckpoint(_ context.Context, chainID uint64, key string) (string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.checkpoints[chainID][key], nil
+}
+
+// SaveCheckpoint stores the checkpoint value, replacing any previous value.
+func (s *memoryStore) SaveCheckpoint(_ context.Context, chainID uint64, key, value string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.checkpoints[chainID] == nil {
+		s.checkpoints[chainID] = make(map[string]string)
+	}
+	s.checkpoints[chainID][key] = value
+
+	return nil
+}
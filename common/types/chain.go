@@ -12,20 +12,52 @@ import (
 // - ChainType: the type of the chain.
 // - ChainID: the unique identifier for the chain.
 // - RpcUrl: the URL for the chain's RPC endpoint.
+// - RpcUrls: the URLs of additional RPC endpoints used for failover and transaction broadcasts.
 // - TxType: the type of transactions supported by the chain.
 // - WaitNBlocks: the number of blocks to wait for transaction confirmation.
 // - PrivateKey: the private key for signing transactions.
+// - RemoteSigner: the external signing service used instead of PrivateKey if set.
+// - Keystore: the encrypted keystore the signing key is loaded from instead of PrivateKey if set.
+// - Mnemonic: the HD wallet the signing key is derived from instead of PrivateKey if set.
+// - Wallets: the additional solver wallets transactions are load-balanced over, besides the wallet of the key above.
 // - RelayReceiver: the address of the relay receiver.
+// - WatchList: the contract events turned into deposit events, the relay receiver and ERC20 transfer events if empty.
+// - CheckpointStore: the store used to persist listener progress, in-memory if nil.
+// - EventDedupStore: the store used to skip events emitted before a restart, in-memory only if nil.
+// - ConfirmEvents: whether deposit events are held until they are WaitNBlocks deep or finalized before being emitted.
+// - DetectNativeTransfers: whether blocks are scanned for native transfers to the solver, including internal transfers where the node supports tracing.
+// - EventQueueSize: the number of events the listener queues in memory for the application, 1000 if zero.
+// - EventQueueOverflow: the policy applied when the event queue is full, EventQueueBlock if empty.
+// - EventSpillStore: the store receiving overflowing events, required by EventQueueSpill.
+// - EventQueueMetrics: receives the event queue metrics, not reported if nil.
+// - OnSubscriptionModeChange: called when the event listener falls back to HTTP polling or recovers WebSocket subscriptions.
+// - MaxPriorityFee: the cap on the priority fee paid per unit of compute (Solana: micro-lamports per compute unit), a chain default if zero.
 type ChainConfig struct {
-	Name          string
-	ChainType     string
-	ChainID       uint64
-	RpcUrl        string
-	TxType        uint64
-	WaitNBlocks   uint64
-	PrivateKey    string
-	SolverAddress string
-	RelayReceiver string
+	Name                     string
+	ChainType                string
+	ChainID                  uint64
+	RpcUrl                   string
+	RpcUrls                  []string
+	TxType                   uint64
+	WaitNBlocks              uint64
+	PrivateKey               string
+	RemoteSigner             *RemoteSignerConfig
+	Keystore                 *KeystoreConfig
+	Mnemonic                 *MnemonicConfig
+	Wallets                  []WalletConfig
+	SolverAddress            string
+	RelayReceiver            string
+	WatchList                []WatchedEvent
+	CheckpointStore          CheckpointStore
+	EventDedupStore          EventDedupStore
+	ConfirmEvents            bool
+	DetectNativeTransfers    bool
+	EventQueueSize           int
+	EventQueueOverflow       EventQueueOverflow
+	EventSpillStore          EventSpillStore
+	EventQueueMetrics        EventQueueMetrics
+	OnSubscriptionModeChange SubscriptionModeChangeFunc
+	MaxPriorityFee           uint64
 }
 
 // GasEstimator provides gas estimation functionality.
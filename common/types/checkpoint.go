@@ -0,0 +1,30 @@
+package types
+
+import "context"
+
+// CheckpointStore persists the progress of chain listeners so they can resume after a restart.
+type CheckpointStore interface {
+	// GetCheckpoint returns the stored checkpoint value.
+	//
+	// Parameters:
+	// - ctx: the context for managing the request.
+	// - chainID: the unique identifier for the chain.
+	// - key: the checkpoint key within the chain.
+	//
+	// Returns:
+	// - string: the stored value, or an empty string if no checkpoint exists.
+	// - error: an error if the checkpoint cannot be loaded.
+	GetCheckpoint(ctx context.Context, chainID uint64, key string) (string, error)
+
+	// SaveCheckpoint stores the checkpoint value, replacing any previous value.
+	//
+	// Parameters:
+	// - ctx: the context for managing the request.
+	// - chainID: the unique identifier for the chain.
+	// - key: the checkpoint key within the chain.
+	// - value: the value to store.
+	//
+	// Returns:
+	// - error: an error if the checkpoint cannot be saved.
+	SaveCheckpoint(ctx context.Context, chainID uint64, key, value string) error
+}
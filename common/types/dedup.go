@@ -0,0 +1,33 @@
+package types
+
+import (
+	"context"
+	"time"
+)
+
+// EventDedupStore records processed chain events so duplicates are skipped across restarts and replays.
+type EventDedupStore interface {
+	// MarkEventProcessed records the event key unless it is already recorded and not expired.
+	//
+	// Parameters:
+	// - ctx: the context for managing the request.
+	// - chainID: the unique identifier for the chain.
+	// - key: the event key within the chain.
+	// - ttl: the time after which the record expires.
+	//
+	// Returns:
+	// - bool: true if the key was recorded by this call, false if the event was already processed.
+	// - error: an error if the record cannot be saved.
+	MarkEventProcessed(ctx context.Context, chainID uint64, key string, ttl time.Duration) (bool, error)
+
+	// ForgetEvent removes the event key so the event can be processed again.
+	//
+	// Parameters:
+	// - ctx: the context for managing the request.
+	// - chainID: the unique identifier for the chain.
+	// - key: the event key within the chain.
+	//
+	// Returns:
+	// - error: an error if the record cannot be removed.
+	ForgetEvent(ctx context.Context, chainID uint64, key string) error
+}
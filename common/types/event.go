@@ -21,6 +21,7 @@ import (
 // - FromTxMinedAt: the time when the transaction was mined.
 // - FromNonce: the nonce of the transaction that emitted the event.
 // - TransactionAmount: the amount of the transaction that emitted the event.
+// - Removed: true if the event is retracted because its block was orphaned by a chain reorganization.
 type ChainEvent struct {
 	ChainID           uint64
 	BlockNumber       uint64
@@ -34,4 +35,5 @@ type ChainEvent struct {
 	TransactionAmount string
 	FromNonce         uint64
 	Metadata          interface{}
+	Removed           bool
 }
@@ -63,6 +63,15 @@ const (
 	// InsufficientBalance indicates that the transfer amount exceeds the available balance.
 	InsufficientBalance SubStatus = "INSUFFICIENT_BALANCE"
 
+	// BlockedAddress indicates that the token blocks the sender or the recipient of the transfer.
+	BlockedAddress SubStatus = "BLOCKED_ADDRESS"
+
+	// TokenPaused indicates that the token transfers are paused.
+	TokenPaused SubStatus = "TOKEN_PAUSED"
+
+	// TransactionReverted indicates that the simulation of the transfer reverted for another reason.
+	TransactionReverted SubStatus = "TRANSACTION_REVERTED"
+
 	// Expired indicates that the transaction expired before processing.
 	Expired SubStatus = "EXPIRED"
 
@@ -0,0 +1,100 @@
+package types
+
+import "context"
+
+// EventQueueOverflow is the policy applied when a listener's event queue is full.
+type EventQueueOverflow string
+
+const (
+	// EventQueueBlock makes the listener wait until the application takes an event from the queue.
+	EventQueueBlock EventQueueOverflow = "block"
+	// EventQueueSpill moves new events to the EventSpillStore until the application catches up.
+	EventQueueSpill EventQueueOverflow = "spill"
+	// EventQueueDropOldest drops the oldest queued event and reports it to the EventQueueMetrics.
+	EventQueueDropOldest EventQueueOverflow = "drop_oldest"
+)
+
+// EventQueueStats is a snapshot of a listener's event queue.
+//
+// Fields:
+// - Depth: the number of events queued in memory.
+// - Capacity: the maximum number of events queued in memory.
+// - Spilling: whether events are waiting in the EventSpillStore.
+// - Delivered: the number of events delivered to the application.
+// - Spilled: the number of events moved to the EventSpillStore.
+// - Dropped: the number of events dropped because the queue was full.
+type EventQueueStats struct {
+	Depth     int
+	Capacity  int
+	Spilling  bool
+	Delivered uint64
+	Spilled   uint64
+	Dropped   uint64
+}
+
+// SpilledEvent is an event kept in the EventSpillStore.
+//
+// Fields:
+// - ID: the position of the event in the store, increasing in spill order.
+// - Payload: the encoded event.
+type SpilledEvent struct {
+	ID      int64
+	Payload []byte
+}
+
+// EventSpillStore keeps the events that overflow a listener's event queue until the application catches up.
+// Events stay in the store until they are delivered, so the events loaded back into memory survive a restart.
+type EventSpillStore interface {
+	// SpillEvent appends an encoded event to the chain's spilled events.
+	//
+	// Parameters:
+	// - ctx: the context for managing the request.
+	// - chainID: the unique identifier for the chain.
+	// - payload: the encoded event.
+	//
+	// Returns:
+	// - error: an error if the event cannot be stored.
+	SpillEvent(ctx context.Context, chainID uint64, payload []byte) error
+
+	// PeekSpilledEvents returns the oldest spilled events of the chain after a position, without removing them.
+	//
+	// Parameters:
+	// - ctx: the context for managing the request.
+	// - chainID: the unique identifier for the chain.
+	// - afterID: the position of the last event already loaded, 0 to start with the oldest event.
+	// - limit: the maximum number of events to return.
+	//
+	// Returns:
+	// - []SpilledEvent: the events, oldest first.
+	// - error: an error if the events cannot be loaded.
+	PeekSpilledEvents(ctx context.Context, chainID uint64, afterID int64, limit int) ([]SpilledEvent, error)
+
+	// DeleteSpilledEvents removes the delivered events of the chain, up to and including a position.
+	//
+	// Parameters:
+	// - ctx: the context for managing the request.
+	// - chainID: the unique identifier for the chain.
+	// - throughID: the position of the last delivered event.
+	//
+	// Returns:
+	// - error: an error if the events cannot be removed.
+	DeleteSpilledEvents(ctx context.Context, chainID uint64, throughID int64) error
+}
+
+// EventQueueMetrics receives the metrics of a listener's event queue.
+// The methods are called synchronously by the listener and must not block.
+type EventQueueMetrics interface {
+	// ObserveQueue reports the state of the queue after an event was queued or delivered.
+	//
+	// Parameters:
+	// - chainID: the unique identifier for the chain.
+	// - stats: the queue state.
+	ObserveQueue(chainID uint64, stats EventQueueStats)
+
+	// EventDropped reports an event dropped because the queue was full.
+	//
+	// Parameters:
+	// - chainID: the unique identifier for the chain.
+	// - event: the dropped event.
+	EventDropped(chainID uint64, event ChainEvent)
+}
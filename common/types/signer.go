@@ -0,0 +1,54 @@
+package types
+
+// RemoteSignerConfig holds the configuration of an external signing service that keeps the chain's key.
+//
+// Fields:
+// - URL: the JSON-RPC endpoint of the signing service, answering eth_signTransaction, eth_sign and eth_signTypedData like Web3Signer.
+// - Address: the address of the signing account, the first account reported by eth_accounts if empty.
+// - AuthToken: the bearer token sent with every request, no authorization header if empty.
+type RemoteSignerConfig struct {
+	URL       string
+	Address   string
+	AuthToken string
+}
+
+// KeystoreConfig holds the location of a go-ethereum V3 JSON keystore file and of its passphrase.
+//
+// Fields:
+// - Path: the path of the keystore file.
+// - PassphraseEnv: the environment variable holding the passphrase.
+// - PassphraseFile: the path of a file holding the passphrase, read if PassphraseEnv is empty.
+type KeystoreConfig struct {
+	Path           string
+	PassphraseEnv  string
+	PassphraseFile string
+}
+
+// MnemonicConfig holds a BIP-39 mnemonic and the BIP-44 derivation path of the chain's key,
+// so the same seed can produce a different solver address on each chain.
+//
+// Fields:
+// - Mnemonic: the mnemonic phrase.
+// - MnemonicEnv: the environment variable holding the mnemonic phrase, read if Mnemonic is empty.
+// - Passphrase: the optional BIP-39 passphrase.
+// - DerivationPath: the BIP-44 derivation path, m/44'/60'/0'/0/0 if empty.
+type MnemonicConfig struct {
+	Mnemonic       string
+	MnemonicEnv    string
+	Passphrase     string
+	DerivationPath string
+}
+
+// WalletConfig holds the key source of an additional solver wallet. Exactly one of the fields must be set.
+//
+// Fields:
+// - PrivateKey: the private key of the wallet.
+// - RemoteSigner: the external signing service keeping the wallet's key.
+// - Keystore: the encrypted keystore the wallet's key is loaded from.
+// - Mnemonic: the HD wallet the wallet's key is derived from.
+type WalletConfig struct {
+	PrivateKey   string
+	RemoteSigner *RemoteSignerConfig
+	Keystore     *KeystoreConfig
+	Mnemonic     *MnemonicConfig
+}
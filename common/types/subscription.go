@@ -28,3 +28,7 @@ func (m SubscriptionMode) String() string {
 		return "Unknown"
 	}
 }
+
+// SubscriptionModeChangeFunc is called when a chain listener switches between WebSocket subscriptions and HTTP polling.
+// It is called synchronously by the listener and must not block.
+type SubscriptionModeChangeFunc func(chainID uint64, from, to SubscriptionMode)
@@ -79,3 +79,23 @@ type Quote struct {
 	Deadline          string     `json:"deadline"`
 	Parameters        Parameters `json:"parameters"`
 }
+
+// SendError is an error of SendAsset telling why the transfer cannot be made.
+//
+// Fields:
+// - SubStatus: the sub-status of the intent the transfer failed for.
+// - Err: the underlying error.
+type SendError struct {
+	SubStatus SubStatus
+	Err       error
+}
+
+// Error returns the message of the underlying error.
+func (e *SendError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *SendError) Unwrap() error {
+	return e.Err
+}
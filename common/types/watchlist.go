@@ -0,0 +1,75 @@
+package types
+
+// QuoteIDSource is where the quote ID of a watched event is read from.
+type QuoteIDSource string
+
+const (
+	// QuoteIDFromEventArg reads the quote ID from a decoded event argument.
+	QuoteIDFromEventArg QuoteIDSource = "event_arg"
+	// QuoteIDFromCalldataSuffix reads the quote ID from the transaction calldata after a fixed offset.
+	QuoteIDFromCalldataSuffix QuoteIDSource = "calldata_suffix"
+	// QuoteIDFromCalldata reads the whole transaction calldata as the quote ID.
+	QuoteIDFromCalldata QuoteIDSource = "calldata"
+	// QuoteIDFromTopic reads the quote ID from an indexed topic of the event.
+	QuoteIDFromTopic QuoteIDSource = "topic"
+)
+
+// AmountSource is where the deposited amount of a watched event is read from.
+type AmountSource string
+
+const (
+	// AmountFromTxValue reads the amount from the native value of the transaction.
+	AmountFromTxValue AmountSource = "tx_value"
+	// AmountFromEventArg reads the amount from a decoded event argument.
+	AmountFromEventArg AmountSource = "event_arg"
+	// AmountFromCallValue reads the amount from the native value of the call that emitted the event,
+	// which differs from the transaction value for deposits made through smart wallets, multicalls or bundlers.
+	AmountFromCallValue AmountSource = "call_value"
+)
+
+// QuoteIDRule describes how the quote ID of a watched event is extracted.
+//
+// Fields:
+// - Source: where the quote ID is read from.
+// - Arg: the event argument holding the quote ID, for QuoteIDFromEventArg.
+// - Offset: the calldata offset the quote ID starts at, for QuoteIDFromCalldataSuffix.
+// - Topic: the index of the topic holding the quote ID, for QuoteIDFromTopic.
+type QuoteIDRule struct {
+	Source QuoteIDSource
+	Arg    string
+	Offset int
+	Topic  int
+}
+
+// AmountRule describes how the deposited amount of a watched event is extracted.
+//
+// Fields:
+// - Source: where the amount is read from.
+// - Arg: the event argument holding the amount, for AmountFromEventArg.
+type AmountRule struct {
+	Source AmountSource
+	Arg    string
+}
+
+// WatchedEvent declares a contract event the chain listener turns into deposit events.
+//
+// Fields:
+// - EventType: the event type reported in the event metadata, the ABI event name if empty.
+// - ABI: the JSON ABI declaring the event.
+// - Event: the name of the event in the ABI.
+// - Contracts: the addresses of the contracts emitting the event, any contract if empty.
+// - SolverTopic: the index of the indexed topic that must hold the solver address, not filtered if zero.
+// - QuoteID: the rule extracting the quote ID.
+// - Amount: the rule extracting the deposited amount.
+// - DepositorArg: the event argument holding the depositor address. If empty, the depositor is the caller
+// of the call that emitted the event for AmountFromCallValue, the transaction sender otherwise.
+type WatchedEvent struct {
+	EventType    string
+	ABI          string
+	Event        string
+	Contracts    []string
+	SolverTopic  int
+	QuoteID      QuoteIDRule
+	Amount       AmountRule
+	DepositorArg string
+}
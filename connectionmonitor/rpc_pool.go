@@ -0,0 +1,286 @@
+package connectionmonitor
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// probeTimeout defines timeout for a single endpoint health probe
+	probeTimeout = 5 * time.Second
+	// healthSmoothing defines weight of the latest sample in the moving averages of latency and error rate
+	healthSmoothing = 0.3
+	// maxHeadLag defines number of blocks an endpoint may trail the highest head before it is unhealthy
+	maxHeadLag = 5
+	// headLagPenalty defines score penalty per block an endpoint trails the highest head
+	headLagPenalty = 500 * time.Millisecond
+	// errorRatePenalty defines score penalty of an endpoint failing every request
+	errorRatePenalty = 10 * time.Second
+	// failoverMargin defines how much better the best endpoint must score to replace a healthy one
+	failoverMargin = time.Second
+)
+
+// ErrNoHealthyEndpoint is returned when no endpoint of the pool is healthy.
+var ErrNoHealthyEndpoint = errors.New("no healthy rpc endpoint")
+
+// EndpointProber represents RPC endpoint probing interface
+type EndpointProber interface {
+	// ProbeHead returns the latest block number reported by the endpoint
+	ProbeHead(ctx context.Context, url string) (uint64, error)
+}
+
+// EndpointHealth holds the health statistics of an RPC endpoint.
+//
+// Fields:
+// - URL: the endpoint URL.
+// - Latency: the moving average of the probe latency.
+// - Head: the latest block number reported by the endpoint.
+// - HeadLag: the number of blocks the endpoint trails the highest head of the pool.
+// - ErrorRate: the moving average of failed requests, between 0 and 1.
+// - Healthy: whether the last probe succeeded and the endpoint is not lagging.
+// - LastChecked: the time of the last probe.
+// - LastError: the error of the last failed request, if any.
+type EndpointHealth struct {
+	URL         string
+	Latency     time.Duration
+	Head        uint64
+	HeadLag     uint64
+	ErrorRate   float64
+	Healthy     bool
+	LastChecked time.Time
+	LastError   error
+}
+
+// Score returns the ranking score of the endpoint, lower is better.
+//
+// Returns:
+// - time.Duration: the latency penalized by head lag and error rate.
+func (h EndpointHealth) Score() time.Duration {
+	return h.Latency +
+		time.Duration(h.HeadLag)*headLagPenalty +
+		time.Duration(h.ErrorRate*float64(errorRatePenalty))
+}
+
+// RPCPool holds the RPC endpoints of a chain and ranks them by health.
+type RPCPool struct {
+	prober    EndpointProber
+	logger    *logrus.Logger
+	chainName string
+	mutex     sync.RWMutex
+	endpoints []*EndpointHealth // Endpoints in configured order.
+}
+
+// NewRPCPool creates a new RPC pool. Endpoints start healthy and ranked in the given order until probed.
+//
+// Parameters:
+// - urls: the endpoint URLs, duplicates and empty URLs are skipped.
+// - prober: the prober used to check the endpoints.
+// - logger: the logger for logging purposes.
+// - chainName: the name of the blockchain chain.
+//
+// Returns:
+// - *RPCPool: the new RPC pool instance.
+// - error: an error if no endpoint is given.
+func NewRPCPool(urls []string, prober EndpointProber, logger *logrus.Logger, chainName string) (*RPCPool, error) {
+	pool := &RPCPool{
+		prober:    prober,
+		logger:    logger,
+		chainName: chainName,
+	}
+
+	seen := make(map[string]bool)
+	for _, url := range urls {
+		if url == "" || seen[url] {
+			continue
+		}
+		seen[url] = true
+		pool.endpoints = append(pool.endpoints, &EndpointHealth{URL: url, Healthy: true})
+	}
+
+	if len(pool.endpoints) == 0 {
+		return nil, errors.Errorf("no rpc endpoints configured for chain %s", chainName)
+	}
+
+	return pool, nil
+}
+
+// URLs returns the endpoint URLs in configured order.
+//
+// Returns:
+// - []string: the endpoint URLs.
+func (p *RPCPool) URLs() []string {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	urls := make([]string, 0, len(p.endpoints))
+	for _, endpoint := range p.endpoints {
+		urls = append(urls, endpoint.URL)
+	}
+	return urls
+}
+
+// CheckHealth probes all endpoints concurrently and updates their statistics.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+func (p *RPCPool) CheckHealth(ctx context.Context) {
+	urls := p.URLs()
+
+	type probeResult struct {
+		head    uint64
+		latency time.Duration
+		err     error
+	}
+
+	results := make([]probeResult, len(urls))
+	var wg sync.WaitGroup
+	for i, url := range urls {
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+
+			probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+			defer cancel()
+
+			start := time.Now()
+			head, err := p.prober.ProbeHead(probeCtx, url)
+			results[i] = probeResult{head: head, latency: time.Since(start), err: err}
+		}(i, url)
+	}
+	wg.Wait()
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	var highestHead uint64
+	for _, result := range results {
+		if result.err == nil && result.head > highestHead {
+			highestHead = result.head
+		}
+	}
+
+	now := time.Now()
+	for i, endpoint := range p.endpoints {
+		result := results[i]
+		endpoint.LastChecked = now
+		p.record(endpoint, result.latency, result.err)
+
+		if result.err != nil {
+			endpoint.Healthy = false
+			p.logger.WithFields(logrus.Fields{
+				"chain": p.chainName,
+				"url":   endpoint.URL,
+				"error": result.err,
+			}).Warn("RPC endpoint probe failed")
+			continue
+		}
+
+		endpoint.Head = result.head
+		endpoint.HeadLag = highestHead - result.head
+		endpoint.Healthy = endpoint.HeadLag <= maxHeadLag
+	}
+}
+
+// RecordResult records the outcome of a request served by an endpoint outside of the health probes.
+//
+// Parameters:
+// - url: the endpoint URL.
+// - latency: the duration of the request.
+// - err: the request error, nil on success.
+func (p *RPCPool) RecordResult(url string, latency time.Duration, err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for _, endpoint := range p.endpoints {
+		if endpoint.URL == url {
+			p.record(endpoint, latency, err)
+			return
+		}
+	}
+}
+
+// record updates the moving averages of an endpoint. The caller must hold the pool mutex.
+func (p *RPCPool) record(endpoint *EndpointHealth, latency time.Duration, err error) {
+	sample := 0.0
+	if err != nil {
+		sample = 1
+		endpoint.LastError = err
+	} else if endpoint.Latency == 0 {
+		endpoint.Latency = latency
+	} else {
+		endpoint.Latency = time.Duration(healthSmoothing*float64(latency) + (1-healthSmoothing)*float64(endpoint.Latency))
+	}
+
+	endpoint.ErrorRate = healthSmoothing*sample + (1-healthSmoothing)*endpoint.ErrorRate
+}
+
+// Ranked returns the endpoints ordered from the best to the worst: healthy endpoints first, then by score.
+//
+// Returns:
+// - []EndpointHealth: the ranked endpoint statistics.
+func (p *RPCPool) Ranked() []EndpointHealth {
+	p.mutex.RLock()
+	ranked := make([]EndpointHealth, 0, len(p.endpoints))
+	for _, endpoint := range p.endpoints {
+		ranked = append(ranked, *endpoint)
+	}
+	p.mutex.RUnlock()
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Healthy != ranked[j].Healthy {
+			return ranked[i].Healthy
+		}
+		return ranked[i].Score() < ranked[j].Score()
+	})
+
+	return ranked
+}
+
+// Health returns the statistics of an endpoint.
+//
+// Parameters:
+// - url: the endpoint URL.
+//
+// Returns:
+// - EndpointHealth: the endpoint statistics.
+// - bool: false if the endpoint is not part of the pool.
+func (p *RPCPool) Health(url string) (EndpointHealth, bool) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	for _, endpoint := range p.endpoints {
+		if endpoint.URL == url {
+			return *endpoint, true
+		}
+	}
+	return EndpointHealth{}, false
+}
+
+// ShouldFailover reports whether requests should move away from the current endpoint, either because it is
+// unhealthy or because a healthy candidate scores better by more than the failover margin.
+//
+// Parameters:
+// - current: the URL of the endpoint currently in use.
+// - candidates: the endpoint statistics eligible to replace it, ranked best first.
+//
+// Returns:
+// - bool: true if the current endpoint should be replaced.
+func (p *RPCPool) ShouldFailover(current string, candidates []EndpointHealth) bool {
+	health, ok := p.Health(current)
+	if !ok || !health.Healthy {
+		return true
+	}
+
+	for _, candidate := range candidates {
+		if candidate.URL == current || !candidate.Healthy {
+			continue
+		}
+		return health.Score()-candidate.Score() > failoverMargin
+	}
+
+	return false
+}
@@ -0,0 +1,72 @@
+package dbconfig
+
+import (
+	"context"
+	"database/sql"
+	"github.com/pkg/errors"
+)
+
+// GetCheckpoint returns the listener checkpoint stored for the given chain ID and key.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - chainID: the unique identifier for the chain.
+// - key: the checkpoint key within the chain.
+//
+// Returns:
+// - string: the stored value, or an empty string if no checkpoint exists.
+// - error: an error if the database operation fails.
+func (dc *DBConfig) GetCheckpoint(ctx context.Context, chainID uint64, key string) (string, error) {
+	db, err := sql.Open("postgres", dc.dbConnStr)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to connect to database")
+	}
+	defer db.Close()
+
+	var value string
+	err = db.QueryRowContext(ctx, `
+		SELECT value
+		FROM listener_checkpoints
+		WHERE chain_id = $1 AND key = $2
+	`, chainID, key).Scan(&value)
+
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get checkpoint")
+	}
+
+	return value, nil
+}
+
+// SaveCheckpoint stores the listener checkpoint for the given chain ID and key.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - chainID: the unique identifier for the chain.
+// - key: the checkpoint key within the chain.
+// - value: the value to store.
+//
+// Returns:
+// - error: an error if the database operation fails.
+func (dc *DBConfig) SaveCheckpoint(ctx context.Context, chainID uint64, key, value string) error {
+	db, err := sql.Open("postgres", dc.dbConnStr)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to database")
+	}
+	defer db.Close()
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO listener_checkpoints (chain_id, key, value, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (chain_id, key)
+		DO UPDATE SET value = EXCLUDED.value, updated_at = NOW()
+	`, chainID, key, value)
+	if err != nil {
+		return errors.Wrap(err, "failed to save checkpoint")
+	}
+
+	return nil
+}
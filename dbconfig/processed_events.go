@@ -0,0 +1,72 @@
+package dbconfig
+
+import (
+	"context"
+	"database/sql"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// MarkEventProcessed records the processed event key for the given chain ID unless it is already recorded and not expired.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - chainID: the unique identifier for the chain.
+// - key: the event key within the chain.
+// - ttl: the time after which the record expires.
+//
+// Returns:
+// - bool: true if the key was recorded by this call, false if the event was already processed.
+// - error: an error if the database operation fails.
+func (dc *DBConfig) MarkEventProcessed(ctx context.Context, chainID uint64, key string, ttl time.Duration) (bool, error) {
+	db, err := sql.Open("postgres", dc.dbConnStr)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to connect to database")
+	}
+	defer db.Close()
+
+	result, err := db.ExecContext(ctx, `
+		INSERT INTO processed_events (chain_id, event_key, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (chain_id, event_key)
+		DO UPDATE SET expires_at = EXCLUDED.expires_at
+		WHERE processed_events.expires_at < NOW()
+	`, chainID, key, time.Now().Add(ttl))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to mark event processed")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to mark event processed")
+	}
+
+	return rows > 0, nil
+}
+
+// ForgetEvent removes the processed event key for the given chain ID.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - chainID: the unique identifier for the chain.
+// - key: the event key within the chain.
+//
+// Returns:
+// - error: an error if the database operation fails.
+func (dc *DBConfig) ForgetEvent(ctx context.Context, chainID uint64, key string) error {
+	db, err := sql.Open("postgres", dc.dbConnStr)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to database")
+	}
+	defer db.Close()
+
+	_, err = db.ExecContext(ctx, `
+		DELETE FROM processed_events
+		WHERE chain_id = $1 AND event_key = $2
+	`, chainID, key)
+	if err != nil {
+		return errors.Wrap(err, "failed to forget event")
+	}
+
+	return nil
+}
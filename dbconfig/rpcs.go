@@ -96,6 +96,30 @@ func (dc *DBConfig) GetRPCsByChainID(ctx context.Context, chainID uint64, active
 	return rpcs, nil
 }
 
+// GetRPCURLsByChainID returns the URLs of all active RPCs for a given chain ID,
+// suitable for ChainConfig.RpcUrl and ChainConfig.RpcUrls.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - chainID: the unique identifier for the chain.
+//
+// Returns:
+// - []string: the RPC URLs.
+// - error: an error if the database operation fails.
+func (dc *DBConfig) GetRPCURLsByChainID(ctx context.Context, chainID uint64) ([]string, error) {
+	rpcs, err := dc.GetRPCsByChainID(ctx, chainID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]string, 0, len(rpcs))
+	for _, rpc := range rpcs {
+		urls = append(urls, rpc.URL)
+	}
+
+	return urls, nil
+}
+
 // GetAgentRPCs returns all RPCs for a given agent ID from the database, optionally filtering by active status.
 //
 // Parameters:
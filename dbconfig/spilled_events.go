@@ -0,0 +1,108 @@
+package dbconfig
+
+import (
+	"context"
+	"database/sql"
+	"github.com/ClipFinance/relay-lib/common/types"
+	"github.com/pkg/errors"
+)
+
+// SpillEvent appends an encoded event to the spilled events of the given chain ID.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - chainID: the unique identifier for the chain.
+// - payload: the encoded event.
+//
+// Returns:
+// - error: an error if the database operation fails.
+func (dc *DBConfig) SpillEvent(ctx context.Context, chainID uint64, payload []byte) error {
+	db, err := sql.Open("postgres", dc.dbConnStr)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to database")
+	}
+	defer db.Close()
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO spilled_events (chain_id, payload)
+		VALUES ($1, $2)
+	`, chainID, payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to spill event")
+	}
+
+	return nil
+}
+
+// PeekSpilledEvents returns the oldest spilled events of the given chain ID after a position, without removing them.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - chainID: the unique identifier for the chain.
+// - afterID: the position of the last event already loaded, 0 to start with the oldest event.
+// - limit: the maximum number of events to return.
+//
+// Returns:
+// - []types.SpilledEvent: the events, oldest first.
+// - error: an error if the database operation fails.
+func (dc *DBConfig) PeekSpilledEvents(ctx context.Context, chainID uint64, afterID int64, limit int) ([]types.SpilledEvent, error) {
+	db, err := sql.Open("postgres", dc.dbConnStr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to database")
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, payload
+		FROM spilled_events
+		WHERE chain_id = $1 AND id > $2
+		ORDER BY id
+		LIMIT $3
+	`, chainID, afterID, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get spilled events")
+	}
+	defer rows.Close()
+
+	var events []types.SpilledEvent
+	for rows.Next() {
+		var event types.SpilledEvent
+		if err := rows.Scan(&event.ID, &event.Payload); err != nil {
+			return nil, errors.Wrap(err, "failed to scan spilled event")
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating spilled events")
+	}
+
+	return events, nil
+}
+
+// DeleteSpilledEvents removes the delivered spilled events of the given chain ID, up to and including a position.
+//
+// Parameters:
+// - ctx: the context for managing the request.
+// - chainID: the unique identifier for the chain.
+// - throughID: the position of the last delivered event.
+//
+// Returns:
+// - error: an error if the database operation fails.
+func (dc *DBConfig) DeleteSpilledEvents(ctx context.Context, chainID uint64, throughID int64) error {
+	db, err := sql.Open("postgres", dc.dbConnStr)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to database")
+	}
+	defer db.Close()
+
+	_, err = db.ExecContext(ctx, `
+		DELETE FROM spilled_events
+		WHERE chain_id = $1 AND id <= $2
+	`, chainID, throughID)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete spilled events")
+	}
+
+	return nil
+}